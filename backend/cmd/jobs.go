@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/jobs"
+	"github.com/liaa-aa/task-manager-project/backend/internal/notify"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+	"github.com/liaa-aa/task-manager-project/backend/internal/storage"
+)
+
+const (
+	trashPurgeInterval = time.Hour
+	reminderInterval   = 30 * time.Second
+	reminderBatchSize  = 100
+)
+
+// startJobs launches the background jobs; they stop when ctx is cancelled.
+func startJobs(ctx context.Context, db *sql.DB, blobStore storage.BlobStore, notifier notify.Notifier) {
+	trash := service.NewTrashService(
+		repository.NewTaskRepository(db),
+		repository.NewCategoryRepository(db),
+		repository.NewAttachmentRepository(db),
+		repository.NewTaskEventRepository(db),
+		blobStore,
+		repository.NewTransactor(db),
+	)
+	go jobs.Every(ctx, "trash purge", trashPurgeInterval, func(ctx context.Context) error {
+		n, err := trash.PurgeExpired(ctx)
+		if n > 0 {
+			log.Printf("trash purge: removed %d items", n)
+		}
+		return err
+	})
+
+	reminders := service.NewReminderService(
+		repository.NewReminderRepository(db),
+		repository.NewTaskRepository(db),
+		repository.NewUserRepositoryPostgres(db),
+		repository.NewTransactor(db),
+		notifier,
+	)
+	// Batches repeat until the backlog is drained, so a burst of reminders
+	// does not wait a full interval per batch.
+	go jobs.Every(ctx, "reminders", reminderInterval, func(ctx context.Context) error {
+		for {
+			n, err := reminders.FireDue(ctx, reminderBatchSize)
+			if err != nil || n < reminderBatchSize {
+				return err
+			}
+		}
+	})
+}
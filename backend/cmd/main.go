@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/liaa-aa/task-manager-project/backend/internal/database"
+	"github.com/liaa-aa/task-manager-project/backend/internal/notify"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
 	"github.com/liaa-aa/task-manager-project/backend/internal/routes"
+	"github.com/liaa-aa/task-manager-project/backend/internal/storage"
 )
 
 func main() {
@@ -20,7 +24,34 @@ func main() {
 
 	log.Println("database connected")
 
-	r := routes.SetupRoutes(db)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := migrateOnStart(db); err != nil {
+		log.Fatal(err)
+	}
+
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "data/attachments"
+	}
+	blobStore, err := storage.NewLocalStore(attachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notifier := notify.NewInbox(repository.NewNotificationRepository(db))
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		notifier = notify.Multi(notifier, notify.NewWebhook(url, os.Getenv("REMINDER_WEBHOOK_SECRET")))
+	}
+
+	startJobs(context.Background(), db, blobStore, notifier)
+
+	r := routes.SetupRoutes(db, blobStore, notifier)
 
 	port := os.Getenv("PORT")
 	if port == "" {
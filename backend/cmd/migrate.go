@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/database"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+func runMigrate(db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.New("steps must be a positive number")
+			}
+			steps = n
+		}
+		reverted, err := database.MigrateDown(ctx, db, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return nil
+
+	case "status":
+		statuses, err := database.MigrationStatuses(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+
+	return errors.New(migrateUsage)
+}
+
+// migrateOnStart brings the schema up to date before the server starts,
+// under the same advisory lock as migrate up, so instances starting together
+// apply each migration once. With AUTO_MIGRATE=false it only refuses to
+// start while migrations are pending, for deployments that run migrate up
+// as a separate step.
+func migrateOnStart(db *sql.DB) error {
+	ctx := context.Background()
+
+	if v := os.Getenv("AUTO_MIGRATE"); v != "" {
+		auto, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("AUTO_MIGRATE must be true or false")
+		}
+		if !auto {
+			statuses, err := database.MigrationStatuses(ctx, db)
+			if err != nil {
+				return err
+			}
+			for _, s := range statuses {
+				if s.AppliedAt == nil {
+					return fmt.Errorf("migration %04d_%s is pending; run migrate up", s.Version, s.Name)
+				}
+			}
+			return nil
+		}
+	}
+
+	applied, err := database.MigrateUp(ctx, db)
+	for _, m := range applied {
+		log.Printf("applied migration %04d_%s", m.Version, m.Name)
+	}
+	return err
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS priorities;
+DROP TABLE IF EXISTS statuses;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
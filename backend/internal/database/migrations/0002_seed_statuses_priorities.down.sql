@@ -0,0 +1,6 @@
+-- Tasks cannot exist without a status and a priority, and by now there is
+-- nothing else to point them at, so seeded rows still in use are kept.
+DELETE FROM priorities p
+WHERE p.id IN (1, 2, 3) AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.priority_id = p.id);
+DELETE FROM statuses s
+WHERE s.id IN (1, 2, 3) AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.status_id = s.id);
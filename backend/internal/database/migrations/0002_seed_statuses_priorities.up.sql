@@ -0,0 +1,14 @@
+INSERT INTO statuses (id, name) VALUES
+    (1, 'Todo'),
+    (2, 'In Progress'),
+    (3, 'Done')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO priorities (id, name) VALUES
+    (1, 'Low'),
+    (2, 'Medium'),
+    (3, 'High')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('statuses', 'id'), (SELECT MAX(id) FROM statuses));
+SELECT setval(pg_get_serial_sequence('priorities', 'id'), (SELECT MAX(id) FROM priorities));
@@ -0,0 +1,30 @@
+-- Move tasks off per-user statuses and priorities onto the built-in one
+-- nearest in position before those rows go.
+UPDATE tasks t
+SET status_id = (
+    SELECT b.id FROM statuses b WHERE b.user_id IS NULL
+    ORDER BY abs(b.position - s.position), b.id LIMIT 1
+)
+FROM statuses s
+WHERE t.status_id = s.id AND s.user_id IS NOT NULL;
+
+UPDATE tasks t
+SET priority_id = (
+    SELECT b.id FROM priorities b WHERE b.user_id IS NULL
+    ORDER BY abs(b.position - p.position), b.id LIMIT 1
+)
+FROM priorities p
+WHERE t.priority_id = p.id AND p.user_id IS NOT NULL;
+
+DELETE FROM statuses WHERE user_id IS NOT NULL;
+DELETE FROM priorities WHERE user_id IS NOT NULL;
+
+ALTER TABLE statuses
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS color;
+
+ALTER TABLE priorities
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS color;
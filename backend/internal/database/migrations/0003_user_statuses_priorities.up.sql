@@ -0,0 +1,15 @@
+ALTER TABLE statuses
+    ADD COLUMN IF NOT EXISTS user_id  UUID REFERENCES users (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS color    TEXT;
+
+ALTER TABLE priorities
+    ADD COLUMN IF NOT EXISTS user_id  UUID REFERENCES users (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS color    TEXT;
+
+UPDATE statuses SET position = id WHERE user_id IS NULL;
+UPDATE priorities SET position = id WHERE user_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS statuses_user_idx ON statuses (user_id);
+CREATE INDEX IF NOT EXISTS priorities_user_idx ON priorities (user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
+DROP TABLE IF EXISTS task_status_changes;
+DROP TABLE IF EXISTS status_transitions;
+ALTER TABLE statuses DROP COLUMN IF EXISTS is_terminal;
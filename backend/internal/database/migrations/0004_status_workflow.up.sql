@@ -0,0 +1,30 @@
+ALTER TABLE statuses ADD COLUMN IF NOT EXISTS is_terminal BOOLEAN NOT NULL DEFAULT false;
+UPDATE statuses SET is_terminal = true WHERE user_id IS NULL AND lower(name) = 'done';
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+    id               SERIAL PRIMARY KEY,
+    user_id          UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_status_id   INT NOT NULL REFERENCES statuses (id) ON DELETE CASCADE,
+    to_status_id     INT NOT NULL REFERENCES statuses (id) ON DELETE CASCADE,
+    requires_comment BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE (user_id, from_status_id, to_status_id)
+);
+
+CREATE TABLE IF NOT EXISTS task_status_changes (
+    id             BIGSERIAL PRIMARY KEY,
+    task_id        UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id        UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_status_id INT NOT NULL,
+    to_status_id   INT NOT NULL,
+    comment        TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_status_changes_task_idx ON task_status_changes (task_id, created_at);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+UPDATE tasks t
+SET completed_at = COALESCE(t.updated_at, t.created_at)
+FROM statuses s
+WHERE s.id = t.status_id AND s.is_terminal AND t.completed_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS recurrence,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS occurrence,
+    DROP COLUMN IF EXISTS scheduled_for;
@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS recurrence    TEXT,
+    ADD COLUMN IF NOT EXISTS series_id     UUID,
+    ADD COLUMN IF NOT EXISTS occurrence    INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_series_idx ON tasks (series_id, occurrence);
@@ -0,0 +1,12 @@
+-- task_events is append-only. task_id carries no foreign key so that the
+-- history of a deleted task survives it.
+CREATE TABLE IF NOT EXISTS task_events (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    UUID NOT NULL,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action     TEXT NOT NULL,
+    changes    JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_idx ON task_events (task_id, id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS task_versions (
+    task_id       UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    version       INT NOT NULL,
+    user_id       UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    snapshot      JSONB NOT NULL,
+    reverted_from INT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, version)
+);
@@ -0,0 +1,11 @@
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS categories_user_name_live_idx;
+ALTER TABLE categories ADD CONSTRAINT categories_user_id_name_key UNIQUE (user_id, name);
+
+DROP INDEX IF EXISTS categories_deleted_at_idx;
+DROP INDEX IF EXISTS tasks_deleted_at_idx;
+
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
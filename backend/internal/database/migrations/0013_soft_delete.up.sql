@@ -0,0 +1,9 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS categories_deleted_at_idx ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trashed categories must not block reusing their name.
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_user_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS categories_user_name_live_idx ON categories (user_id, name) WHERE deleted_at IS NULL;
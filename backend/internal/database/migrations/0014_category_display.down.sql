@@ -0,0 +1,4 @@
+ALTER TABLE categories DROP COLUMN IF EXISTS archived;
+ALTER TABLE categories DROP COLUMN IF EXISTS position;
+ALTER TABLE categories DROP COLUMN IF EXISTS icon;
+ALTER TABLE categories DROP COLUMN IF EXISTS color;
@@ -0,0 +1,13 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS color TEXT;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS icon TEXT;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT false;
+
+-- Keep the alphabetical order users saw before positions existed.
+UPDATE categories c
+SET position = ranked.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY name) AS rn
+    FROM categories
+) ranked
+WHERE c.id = ranked.id AND c.position = 0;
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS categories_user_parent_name_live_idx;
+
+-- Subcategories under different parents may share a name, which the flat
+-- index forbids. Keep the oldest of each name and suffix the others with
+-- the start of their id.
+UPDATE categories c
+SET name = c.name || ' (' || left(c.id::text, 8) || ')'
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS n
+    FROM categories
+    WHERE deleted_at IS NULL
+) dup
+WHERE dup.id = c.id AND dup.n > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS categories_user_name_live_idx ON categories (user_id, name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS categories_parent_idx;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,9 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES categories (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS categories_parent_idx ON categories (parent_id);
+
+-- Names only need to be unique among siblings now.
+DROP INDEX IF EXISTS categories_user_name_live_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS categories_user_parent_name_live_idx
+    ON categories (user_id, COALESCE(parent_id::text, ''), name)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- revision counts writes to a task; it backs the ETag used for optimistic
+-- concurrency on the task endpoints.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 1;
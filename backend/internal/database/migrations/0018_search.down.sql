@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS categories_search_idx;
+
+DROP INDEX IF EXISTS task_comments_search_idx;
+ALTER TABLE task_comments DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS tasks_search_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,14 @@
+-- The 'simple' configuration skips stemming, so prefix matches behave the
+-- same whatever language a task is written in.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS tasks_search_idx ON tasks USING GIN (search_vector);
+
+ALTER TABLE task_comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED;
+CREATE INDEX IF NOT EXISTS task_comments_search_idx ON task_comments USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS categories_search_idx ON categories USING GIN (to_tsvector('simple', name));
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Due dates so far were calendar days stored as midnight UTC.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_all_day BOOLEAN NOT NULL DEFAULT false;
+UPDATE tasks SET due_all_day = true WHERE due_date IS NOT NULL;
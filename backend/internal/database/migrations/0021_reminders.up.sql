@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id        UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id        UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    remind_at      TIMESTAMPTZ,
+    offset_minutes INT CHECK (offset_minutes >= 0),
+    -- fire_at is the next time to fire: the rule's time, or a later one after
+    -- a snooze or a failed delivery. It is NULL for an offset reminder on a
+    -- task without a due date.
+    fire_at        TIMESTAMPTZ,
+    fired_at       TIMESTAMPTZ,
+    attempts       INT NOT NULL DEFAULT 0,
+    last_error     TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS reminders_task_idx ON reminders (task_id);
+CREATE INDEX IF NOT EXISTS reminders_pending_idx ON reminders (fire_at) WHERE fired_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    task_id     UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    reminder_id UUID REFERENCES reminders (id) ON DELETE SET NULL,
+    title       TEXT NOT NULL,
+    body        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, created_at DESC);
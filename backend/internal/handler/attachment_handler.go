@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type AttachmentHandler struct {
+	AttachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{AttachmentService: attachmentService}
+}
+
+// multipartOverhead leaves room for part headers and boundaries on top of the
+// file itself when capping the request body.
+const multipartOverhead = 1 << 20
+
+func attachmentError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, service.ErrAttachmentNotFound):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, service.ErrAttachmentTooLarge), errors.As(err, &maxErr):
+		http.Error(w, service.ErrAttachmentTooLarge.Error(), 413)
+	case errors.Is(err, service.ErrAttachmentType):
+		http.Error(w, err.Error(), 415)
+	default:
+		http.Error(w, err.Error(), 400)
+	}
+}
+
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	attachments, err := h.AttachmentService.List(r.Context(), id, userID)
+	if err != nil {
+		attachmentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(attachments)
+}
+
+// Upload expects a multipart/form-data body with the file in the "file" part.
+// The part is streamed to the blob store rather than buffered in memory.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.AttachmentService.MaxSize()+multipartOverhead)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "expected multipart/form-data", 400)
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			http.Error(w, "missing file part", 400)
+			return
+		}
+		if err != nil {
+			attachmentError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.AttachmentService.Upload(r.Context(), id, userID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			attachmentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(201)
+		_ = json.NewEncoder(w).Encode(attachment)
+		return
+	}
+}
+
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	attachment, body, err := h.AttachmentService.Open(r.Context(), id, attachmentID, userID)
+	if err != nil {
+		attachmentError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = io.Copy(w, body)
+}
+
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	if err := h.AttachmentService.Delete(r.Context(), id, attachmentID, userID); err != nil {
+		attachmentError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
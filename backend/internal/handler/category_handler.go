@@ -2,10 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
 	"github.com/liaa-aa/task-manager-project/backend/internal/service"
 )
 
@@ -18,7 +21,20 @@ func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandle
 }
 
 type categoryReq struct {
-	Name string `json:"name"`
+	ParentID *string `json:"parent_id"`
+	Name     string  `json:"name"`
+	Color    *string `json:"color"`
+	Icon     *string `json:"icon"`
+	Position *int    `json:"position"`
+	Archived bool    `json:"archived"`
+}
+
+type categoryOrderReq struct {
+	IDs []string `json:"ids"`
+}
+
+type categoryMoveReq struct {
+	ParentID *string `json:"parent_id"`
 }
 
 func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -28,7 +44,8 @@ func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	categories, err := h.CategoryService.List(r.Context(), userID)
+	includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("include_archived"))
+	categories, err := h.CategoryService.List(r.Context(), userID, includeArchived)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
@@ -51,8 +68,18 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := h.CategoryService.Create(r.Context(), userID, req.Name)
-	if err != nil {
+	category := &model.Category{
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Name:     req.Name,
+		Color:    req.Color,
+		Icon:     req.Icon,
+		Archived: req.Archived,
+	}
+	if req.Position != nil {
+		category.Position = *req.Position
+	}
+	if err := h.CategoryService.Create(r.Context(), category); err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
@@ -62,6 +89,81 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(category)
 }
 
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var req categoryReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	category := &model.Category{
+		ID:       id,
+		UserID:   userID,
+		Name:     req.Name,
+		Color:    req.Color,
+		Icon:     req.Icon,
+		Archived: req.Archived,
+	}
+	if err := h.CategoryService.Update(r.Context(), category, req.Position); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(category)
+}
+
+func (h *CategoryHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req categoryOrderReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	if err := h.CategoryService.Reorder(r.Context(), userID, req.IDs); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *CategoryHandler) Move(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var req categoryMoveReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	category, err := h.CategoryService.Move(r.Context(), id, userID, req.ParentID)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(category)
+}
+
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r.Context())
 	if !ok {
@@ -70,11 +172,19 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	id := chi.URLParam(r, "id")
-	err := h.CategoryService.Delete(r.Context(), id, userID)
+	affected, err := h.CategoryService.Delete(r.Context(), id, userID, r.URL.Query().Get("tasks"))
+	var tasksErr *service.CategoryTasksError
+	if errors.As(err, &tasksErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(409)
+		_ = json.NewEncoder(w).Encode(tasksErr)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
 
-	w.WriteHeader(204)
-}
\ No newline at end of file
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]int{"tasks_affected": affected})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type CommentHandler struct {
+	CommentService service.CommentService
+}
+
+func NewCommentHandler(commentService service.CommentService) *CommentHandler {
+	return &CommentHandler{CommentService: commentService}
+}
+
+type commentReq struct {
+	Body string `json:"body"`
+}
+
+func commentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrCommentNotFound):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, service.ErrCommentForbidden):
+		http.Error(w, err.Error(), 403)
+	default:
+		http.Error(w, err.Error(), 400)
+	}
+}
+
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	comments, err := h.CommentService.List(r.Context(), id, userID)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(comments)
+}
+
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var req commentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	comment, err := h.CommentService.Create(r.Context(), id, userID, req.Body)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	var req commentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	comment, err := h.CommentService.Update(r.Context(), id, commentID, userID, req.Body)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	if err := h.CommentService.Delete(r.Context(), id, commentID, userID); err != nil {
+		commentError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
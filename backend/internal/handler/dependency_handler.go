@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type DependencyHandler struct {
+	DependencyService service.DependencyService
+}
+
+func NewDependencyHandler(dependencyService service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{DependencyService: dependencyService}
+}
+
+type dependencyReq struct {
+	BlockedBy string `json:"blocked_by"`
+}
+
+func (h *DependencyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	deps, err := h.DependencyService.List(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(deps)
+}
+
+func (h *DependencyHandler) Add(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var req dependencyReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	dep, err := h.DependencyService.Add(r.Context(), userID, req.BlockedBy, id)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(dep)
+}
+
+func (h *DependencyHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	blockerID := chi.URLParam(r, "blockerID")
+
+	if err := h.DependencyService.Remove(r.Context(), userID, blockerID, id); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *DependencyHandler) Order(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	tasks, err := h.DependencyService.Order(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tasks)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type ReminderHandler struct {
+	ReminderService service.ReminderService
+}
+
+func NewReminderHandler(reminderService service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{ReminderService: reminderService}
+}
+
+type reminderReq struct {
+	RemindAt      *time.Time `json:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes"`
+}
+
+// snoozeReq sets either an end time or a number of minutes from now.
+type snoozeReq struct {
+	Until   *time.Time `json:"until"`
+	Minutes int        `json:"minutes"`
+}
+
+func reminderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrReminderNotFound):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, service.ErrInvalidReminder):
+		http.Error(w, err.Error(), 400)
+	default:
+		http.Error(w, err.Error(), 500)
+	}
+}
+
+func (h *ReminderHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	reminders, err := h.ReminderService.List(r.Context(), id, userID)
+	if err != nil {
+		reminderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reminders)
+}
+
+func (h *ReminderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var req reminderReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	reminder, err := h.ReminderService.Create(r.Context(), id, userID, service.ReminderInput{
+		RemindAt:      req.RemindAt,
+		OffsetMinutes: req.OffsetMinutes,
+	})
+	if err != nil {
+		reminderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(reminder)
+}
+
+func (h *ReminderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	reminderID := chi.URLParam(r, "reminderID")
+
+	if err := h.ReminderService.Delete(r.Context(), id, reminderID, userID); err != nil {
+		reminderError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *ReminderHandler) Snooze(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	reminderID := chi.URLParam(r, "reminderID")
+
+	var req snoozeReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+	if (req.Until == nil) == (req.Minutes == 0) {
+		http.Error(w, "set exactly one of until and minutes", 400)
+		return
+	}
+	until := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	if req.Until != nil {
+		until = *req.Until
+	}
+
+	reminder, err := h.ReminderService.Snooze(r.Context(), id, reminderID, userID, until)
+	if err != nil {
+		reminderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reminder)
+}
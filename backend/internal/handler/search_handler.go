@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type SearchHandler struct {
+	SearchService service.SearchService
+}
+
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{SearchService: searchService}
+}
+
+// Search takes q, an optional comma-separated types list and any of the
+// task list filters.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	var kinds []string
+	if v := r.URL.Query().Get("types"); v != "" {
+		kinds = strings.Split(v, ",")
+	}
+
+	results, err := h.SearchService.Search(r.Context(), userID, r.URL.Query().Get("q"), kinds, filter)
+	if errors.Is(err, service.ErrInvalidSearch) || errors.Is(err, service.ErrInvalidTaskFilter) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(results)
+}
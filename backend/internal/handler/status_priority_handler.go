@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type StatusPriorityHandler struct {
+	StatusPriorityService service.StatusPriorityService
+}
+
+func NewStatusPriorityHandler(statusPriorityService service.StatusPriorityService) *StatusPriorityHandler {
+	return &StatusPriorityHandler{StatusPriorityService: statusPriorityService}
+}
+
+type levelReq struct {
+	Name       string  `json:"name"`
+	Position   int     `json:"position"`
+	Color      *string `json:"color"`
+	IsTerminal bool    `json:"is_terminal"`
+}
+
+func (h *StatusPriorityHandler) ListStatuses(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	statuses, err := h.StatusPriorityService.ListStatuses(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(statuses)
+}
+
+func (h *StatusPriorityHandler) CreateStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req levelReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	status := &model.Status{
+		UserID:     &userID,
+		Name:       req.Name,
+		Position:   req.Position,
+		Color:      req.Color,
+		IsTerminal: req.IsTerminal,
+	}
+	if err := h.StatusPriorityService.CreateStatus(r.Context(), status); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func (h *StatusPriorityHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid status ID", 400)
+		return
+	}
+
+	var req levelReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	status := &model.Status{
+		ID:         id,
+		UserID:     &userID,
+		Name:       req.Name,
+		Position:   req.Position,
+		Color:      req.Color,
+		IsTerminal: req.IsTerminal,
+	}
+	if err := h.StatusPriorityService.UpdateStatus(r.Context(), status); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func (h *StatusPriorityHandler) DeleteStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid status ID", 400)
+		return
+	}
+
+	if err := h.StatusPriorityService.DeleteStatus(r.Context(), id, userID); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *StatusPriorityHandler) ListPriorities(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	priorities, err := h.StatusPriorityService.ListPriorities(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(priorities)
+}
+
+func (h *StatusPriorityHandler) CreatePriority(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req levelReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	priority := &model.Priority{
+		UserID:   &userID,
+		Name:     req.Name,
+		Position: req.Position,
+		Color:    req.Color,
+	}
+	if err := h.StatusPriorityService.CreatePriority(r.Context(), priority); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(priority)
+}
+
+func (h *StatusPriorityHandler) UpdatePriority(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid priority ID", 400)
+		return
+	}
+
+	var req levelReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	priority := &model.Priority{
+		ID:       id,
+		UserID:   &userID,
+		Name:     req.Name,
+		Position: req.Position,
+		Color:    req.Color,
+	}
+	if err := h.StatusPriorityService.UpdatePriority(r.Context(), priority); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(priority)
+}
+
+func (h *StatusPriorityHandler) DeletePriority(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid priority ID", 400)
+		return
+	}
+
+	if err := h.StatusPriorityService.DeletePriority(r.Context(), id, userID); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -2,13 +2,19 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
 	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
 	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+	"github.com/liaa-aa/task-manager-project/backend/internal/taskquery"
 )
 
 type TaskHandler struct {
@@ -20,26 +26,198 @@ func NewTaskHandler(taskService service.TaskService) *TaskHandler {
 }
 
 type taskReq struct {
-	CategoryID   *string `json:"category_id"`   
-	CategoryName string  `json:"category_name"` 
-	StatusID     int     `json:"status_id"`
-	PriorityID   int     `json:"priority_id"`
-	Title        string  `json:"title"`
-	Description  *string `json:"description"`
-	DueDate      *string `json:"due_date"` 
+	ParentID      *string  `json:"parent_id"`
+	CategoryID    *string  `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	StatusID      int      `json:"status_id"`
+	PriorityID    int      `json:"priority_id"`
+	Title         string   `json:"title"`
+	Description   *string  `json:"description"`
+	DueDate       *string  `json:"due_date"`
+	DueAllDay     *bool    `json:"due_all_day"`
+	Recurrence    *string  `json:"recurrence"`
+	Tags          []string `json:"tags"`
+	StatusComment string   `json:"status_comment"`
 }
 
-func parseDueDate(s *string) (*time.Time, error) {
+// parseDueDate reads YYYY-MM-DD as an all-day date or an RFC 3339
+// date-time as a point in time, unless allDay says otherwise.
+func parseDueDate(s *string, allDay *bool) (*time.Time, bool, error) {
 	if s == nil || *s == "" {
+		return nil, false, nil
+	}
+	t, isAllDay, err := service.ParseDueDateAs(*s, allDay)
+	if err != nil {
+		return nil, false, err
+	}
+	return &t, isAllDay, nil
+}
+
+// parseDay reads a YYYY-MM-DD calendar day.
+func parseDay(s string) (*time.Time, error) {
+	if s == "" {
 		return nil, nil
 	}
-	t, err := time.Parse("2006-01-02", *s)
+	t, err := time.Parse("2006-01-02", s)
 	if err != nil {
 		return nil, err
 	}
 	return &t, nil
 }
 
+func parseOptionalInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+type bulkTaskReq struct {
+	IDs           []string `json:"ids"`
+	Action        string   `json:"action"`
+	Mode          string   `json:"mode"`
+	StatusID      int      `json:"status_id"`
+	PriorityID    int      `json:"priority_id"`
+	CategoryID    *string  `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	DueDate       *string  `json:"due_date"`
+	Days          int      `json:"days"`
+	Children      string   `json:"children"`
+	StatusComment string   `json:"status_comment"`
+	Force         bool     `json:"force"`
+}
+
+// quickTaskReq carries a quick-add line. Timezone is an IANA name used for
+// relative dates and defaults to the user's own.
+type quickTaskReq struct {
+	Text     string `json:"text"`
+	Timezone string `json:"timezone"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// taskETag is the strong entity tag for a task's current revision.
+func taskETag(t *model.Task) string {
+	return `"` + strconv.Itoa(t.Revision) + `"`
+}
+
+// parseIfMatch reads If-Match as a list of task revisions. It returns nil
+// when the header is absent or "*". Weak or foreign tags never match, so a
+// header made only of those yields an empty list.
+func parseIfMatch(r *http.Request) []int {
+	header := strings.Join(r.Header.Values("If-Match"), ",")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return nil
+	}
+	revisions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if n, err := strconv.Atoi(tag[1 : len(tag)-1]); err == nil {
+			revisions = append(revisions, n)
+		}
+	}
+	return revisions
+}
+
+// noneMatch reports whether If-None-Match lets a read of etag through. It
+// uses the weak comparison RFC 9110 asks for on GET.
+func noneMatch(r *http.Request, etag string) bool {
+	header := strings.Join(r.Header.Values("If-None-Match"), ",")
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return false
+		}
+	}
+	return true
+}
+
+func parseTaskFilter(r *http.Request) (repository.TaskFilter, error) {
+	q := r.URL.Query()
+	var f repository.TaskFilter
+	var err error
+
+	if f.StatusID, err = parseOptionalInt(q.Get("status_id")); err != nil {
+		return f, errors.New("status_id must be a number")
+	}
+	if f.PriorityID, err = parseOptionalInt(q.Get("priority_id")); err != nil {
+		return f, errors.New("priority_id must be a number")
+	}
+	if v := q.Get("category_id"); v != "" {
+		f.CategoryID = &v
+	}
+	if v := q.Get("include_subcategories"); v != "" {
+		if f.IncludeSubcategories, err = strconv.ParseBool(v); err != nil {
+			return f, errors.New("include_subcategories must be true or false")
+		}
+	}
+
+	if f.DueFrom, err = parseDay(q.Get("due_from")); err != nil {
+		return f, errors.New("due_from must be YYYY-MM-DD")
+	}
+	if f.DueTo, err = parseDay(q.Get("due_to")); err != nil {
+		return f, errors.New("due_to must be YYYY-MM-DD")
+	}
+
+	if v := q.Get("overdue"); v != "" {
+		if f.Overdue, err = strconv.ParseBool(v); err != nil {
+			return f, errors.New("overdue must be true or false")
+		}
+	}
+
+	if v := q.Get("completed"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, errors.New("completed must be true or false")
+		}
+		f.Completed = &b
+	}
+
+	if v := q.Get("blocked"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, errors.New("blocked must be true or false")
+		}
+		f.Blocked = &b
+	}
+
+	if v := q.Get("archived"); v != "" {
+		if f.Archived, err = strconv.ParseBool(v); err != nil {
+			return f, errors.New("archived must be true or false")
+		}
+	}
+
+	if v := q.Get("tags"); v != "" {
+		f.Tags = strings.Split(v, ",")
+	}
+	f.TagMode = strings.ToLower(q.Get("tag_mode"))
+
+	f.Search = q.Get("search")
+	f.Sort = q.Get("sort")
+	f.Order = strings.ToLower(q.Get("order"))
+
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			return f, errors.New("limit must be a number")
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		if f.Cursor, err = repository.DecodeTaskCursor(v); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
 func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r.Context())
 	if !ok {
@@ -47,13 +225,31 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.TaskService.List(r.Context(), userID)
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	// q is read here rather than in parseTaskFilter: /search uses q for its
+	// search text.
+	if v := r.URL.Query().Get("q"); v != "" {
+		if filter.Query, err = taskquery.Parse(v); err != nil {
+			http.Error(w, "q: "+err.Error(), 400)
+			return
+		}
+	}
+
+	page, err := h.TaskService.List(r.Context(), userID, filter)
+	if errors.Is(err, service.ErrInvalidTaskFilter) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(tasks)
+	_ = json.NewEncoder(w).Encode(page)
 }
 
 func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -74,6 +270,12 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	etag := taskETag(task)
+	w.Header().Set("ETag", etag)
+	if !noneMatch(r, etag) {
+		w.WriteHeader(304)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(task)
 }
@@ -91,20 +293,24 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	due, err := parseDueDate(req.DueDate)
+	due, allDay, err := parseDueDate(req.DueDate, req.DueAllDay)
 	if err != nil {
-		http.Error(w, "due_date must be YYYY-MM-DD", 400)
+		http.Error(w, err.Error(), 400)
 		return
 	}
 
 	t := &model.Task{
 		UserID:      userID,
-		CategoryID:  req.CategoryID, 
+		ParentID:    req.ParentID,
+		CategoryID:  req.CategoryID,
 		StatusID:    req.StatusID,
 		PriorityID:  req.PriorityID,
 		Title:       req.Title,
 		Description: req.Description,
 		DueDate:     due,
+		DueAllDay:   allDay,
+		Recurrence:  req.Recurrence,
+		Tags:        req.Tags,
 	}
 
 	err = h.TaskService.Create(r.Context(), t, req.CategoryName)
@@ -113,6 +319,7 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(t))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(201)
 	_ = json.NewEncoder(w).Encode(t)
@@ -132,33 +339,110 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	due, err := parseDueDate(req.DueDate)
+	due, allDay, err := parseDueDate(req.DueDate, req.DueAllDay)
 	if err != nil {
-		http.Error(w, "due_date must be YYYY-MM-DD", 400)
+		http.Error(w, err.Error(), 400)
 		return
 	}
 
 	t := &model.Task{
 		ID:          id,
 		UserID:      userID,
-		CategoryID:  req.CategoryID, 
+		ParentID:    req.ParentID,
+		CategoryID:  req.CategoryID,
 		StatusID:    req.StatusID,
 		PriorityID:  req.PriorityID,
 		Title:       req.Title,
 		Description: req.Description,
 		DueDate:     due,
+		DueAllDay:   allDay,
+		Recurrence:  req.Recurrence,
+		Tags:        req.Tags,
 	}
 
-	err = h.TaskService.Update(r.Context(), t, req.CategoryName)
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	err = h.TaskService.Update(r.Context(), t, req.CategoryName, service.TaskUpdateOptions{
+		StatusComment: req.StatusComment,
+		Force:         force,
+		Scope:         r.URL.Query().Get("scope"),
+		IfMatch:       parseIfMatch(r),
+	})
+	var transitionErr *service.TransitionError
+	if errors.As(err, &transitionErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(422)
+		_ = json.NewEncoder(w).Encode(transitionErr)
+		return
+	}
+	if errors.Is(err, service.ErrTaskModified) {
+		http.Error(w, err.Error(), 412)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(t))
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(t)
 }
 
+// Patch applies a JSON Merge Patch: absent fields stay as they are and null
+// clears them. status_comment is read from the document but not stored.
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	var patch service.TaskPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	var statusComment string
+	if raw, ok := patch["status_comment"]; ok {
+		if err := json.Unmarshal(raw, &statusComment); err != nil {
+			http.Error(w, "status_comment must be a string", 400)
+			return
+		}
+		delete(patch, "status_comment")
+	}
+
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	task, err := h.TaskService.Patch(r.Context(), id, userID, patch, service.TaskUpdateOptions{
+		StatusComment: statusComment,
+		Force:         force,
+		Scope:         r.URL.Query().Get("scope"),
+		IfMatch:       parseIfMatch(r),
+	})
+	var transitionErr *service.TransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(422)
+		_ = json.NewEncoder(w).Encode(transitionErr)
+		return
+	case errors.Is(err, service.ErrTaskNotFound):
+		http.Error(w, "not found", 404)
+		return
+	case errors.Is(err, service.ErrTaskModified):
+		http.Error(w, err.Error(), 412)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(task)
+}
+
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r.Context())
 	if !ok {
@@ -167,9 +451,231 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, "id")
 
-	if err := h.TaskService.Delete(r.Context(), id, userID); err != nil {
+	mode := repository.ChildDeleteMode(r.URL.Query().Get("children"))
+	err := h.TaskService.Delete(r.Context(), id, userID, mode, parseIfMatch(r))
+	if errors.Is(err, service.ErrInvalidChildDeleteMode) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if errors.Is(err, service.ErrTaskModified) {
+		http.Error(w, err.Error(), 412)
+		return
+	}
+	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
 	w.WriteHeader(204)
 }
+
+func (h *TaskHandler) Children(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	depth := 0
+	if v := r.URL.Query().Get("depth"); v != "" && v != "all" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "depth must be a number or all", 400)
+			return
+		}
+		depth = n
+	}
+
+	children, err := h.TaskService.Children(r.Context(), id, userID, depth)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(children)
+}
+
+func (h *TaskHandler) History(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	events, err := h.TaskService.History(r.Context(), id, userID)
+	if errors.Is(err, service.ErrTaskNotFound) {
+		http.Error(w, "not found", 404)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(events)
+}
+
+func (h *TaskHandler) Versions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	versions, err := h.TaskService.Versions(r.Context(), id, userID)
+	if errors.Is(err, service.ErrTaskNotFound) {
+		http.Error(w, "not found", 404)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(versions)
+}
+
+type revertReq struct {
+	StatusComment string `json:"status_comment"`
+}
+
+func (h *TaskHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil || version < 1 {
+		http.Error(w, "version must be a positive number", 400)
+		return
+	}
+
+	// The body is optional; it only carries a comment for a status change
+	// that requires one.
+	var req revertReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	task, err := h.TaskService.Revert(r.Context(), id, userID, version, req.StatusComment)
+	var transitionErr *service.TransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(422)
+		_ = json.NewEncoder(w).Encode(transitionErr)
+		return
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrTaskVersionNotFound):
+		http.Error(w, err.Error(), 404)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(task)
+}
+
+// Bulk applies one action to many tasks. An atomic run that had to roll
+// back answers 422 with the same per-item report.
+func (h *TaskHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req bulkTaskReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	due, allDay, err := parseDueDate(req.DueDate, nil)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	result, err := h.TaskService.Bulk(r.Context(), userID, service.BulkTaskOp{
+		TaskIDs:       req.IDs,
+		Action:        req.Action,
+		Mode:          req.Mode,
+		StatusID:      req.StatusID,
+		PriorityID:    req.PriorityID,
+		CategoryID:    req.CategoryID,
+		CategoryName:  req.CategoryName,
+		DueDate:       due,
+		DueAllDay:     allDay,
+		ShiftDays:     req.Days,
+		Children:      repository.ChildDeleteMode(req.Children),
+		StatusComment: req.StatusComment,
+		Force:         req.Force,
+	})
+	if errors.Is(err, service.ErrInvalidBulkRequest) || errors.Is(err, service.ErrInvalidChildDeleteMode) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Applied {
+		w.WriteHeader(422)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// Quick creates a task from one line of text, or with dry_run only shows
+// how the text was read.
+func (h *TaskHandler) Quick(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req quickTaskReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	var loc *time.Location
+	if req.Timezone != "" {
+		var err error
+		if loc, err = service.LoadTimezone(req.Timezone); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	}
+
+	result, err := h.TaskService.QuickAdd(r.Context(), userID, req.Text, loc, req.DryRun)
+	if errors.Is(err, service.ErrInvalidQuickAdd) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.DryRun {
+		w.Header().Set("ETag", taskETag(result.Task))
+		w.WriteHeader(201)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type TrashHandler struct {
+	TrashService service.TrashService
+}
+
+func NewTrashHandler(trashService service.TrashService) *TrashHandler {
+	return &TrashHandler{TrashService: trashService}
+}
+
+func trashError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrTrashItemNotFound):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, service.ErrInvalidTrashKind):
+		http.Error(w, err.Error(), 400)
+	case errors.Is(err, service.ErrTrashNameTaken):
+		http.Error(w, err.Error(), 409)
+	default:
+		http.Error(w, err.Error(), 500)
+	}
+}
+
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	trash, err := h.TrashService.List(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(trash)
+}
+
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	if err := h.TrashService.Restore(r.Context(), chi.URLParam(r, "kind"), chi.URLParam(r, "id"), userID); err != nil {
+		trashError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	if err := h.TrashService.Purge(r.Context(), chi.URLParam(r, "kind"), chi.URLParam(r, "id"), userID); err != nil {
+		trashError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *TrashHandler) Empty(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	if err := h.TrashService.Empty(r.Context(), userID); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.WriteHeader(204)
+}
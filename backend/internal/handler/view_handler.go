@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type ViewHandler struct {
+	ViewService service.ViewService
+}
+
+func NewViewHandler(viewService service.ViewService) *ViewHandler {
+	return &ViewHandler{ViewService: viewService}
+}
+
+type viewReq struct {
+	Name  string          `json:"name"`
+	Query model.ViewQuery `json:"query"`
+}
+
+func viewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrViewNotFound):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, service.ErrInvalidView), errors.Is(err, service.ErrInvalidTaskFilter):
+		http.Error(w, err.Error(), 400)
+	default:
+		http.Error(w, err.Error(), 500)
+	}
+}
+
+func (h *ViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	views, err := h.ViewService.List(r.Context(), userID)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(views)
+}
+
+func (h *ViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	view, err := h.ViewService.Get(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req viewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	view := &model.SavedView{UserID: userID, Name: req.Name, Query: req.Query}
+	if err := h.ViewService.Create(r.Context(), view); err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req viewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	view := &model.SavedView{ID: chi.URLParam(r, "id"), UserID: userID, Name: req.Name, Query: req.Query}
+	if err := h.ViewService.Update(r.Context(), view); err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	if err := h.ViewService.Delete(r.Context(), chi.URLParam(r, "id"), userID); err != nil {
+		viewError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// Tasks runs the view; pass next_cursor back as cursor for the next page.
+func (h *ViewHandler) Tasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var cursor *repository.TaskCursor
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		var err error
+		if cursor, err = repository.DecodeTaskCursor(v); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	}
+
+	result, err := h.ViewService.Tasks(r.Context(), chi.URLParam(r, "id"), userID, cursor)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+)
+
+type WorkflowHandler struct {
+	WorkflowService service.WorkflowService
+}
+
+func NewWorkflowHandler(workflowService service.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{WorkflowService: workflowService}
+}
+
+type transitionReq struct {
+	FromStatusID    int  `json:"from_status_id"`
+	ToStatusID      int  `json:"to_status_id"`
+	RequiresComment bool `json:"requires_comment"`
+}
+
+func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	wf, err := h.WorkflowService.Get(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(wf)
+}
+
+func (h *WorkflowHandler) AddTransition(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	var req transitionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid payload", 400)
+		return
+	}
+
+	t := &model.StatusTransition{
+		UserID:          userID,
+		FromStatusID:    req.FromStatusID,
+		ToStatusID:      req.ToStatusID,
+		RequiresComment: req.RequiresComment,
+	}
+	if err := h.WorkflowService.AddTransition(r.Context(), t); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(t)
+}
+
+func (h *WorkflowHandler) RemoveTransition(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid transition ID", 400)
+		return
+	}
+
+	if err := h.WorkflowService.RemoveTransition(r.Context(), id, userID); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
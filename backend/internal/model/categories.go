@@ -3,8 +3,15 @@ package model
 import "time"
 
 type Category struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	ParentID  *string    `json:"parent_id,omitempty"`
+	Name      string     `json:"name"`
+	Color     *string    `json:"color,omitempty"`
+	Icon      *string    `json:"icon,omitempty"`
+	Position  int        `json:"position"`
+	Archived  bool       `json:"archived"`
+	TaskCount int        `json:"task_count"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
\ No newline at end of file
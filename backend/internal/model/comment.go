@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Comment is a note on a task. Body holds raw Markdown; rendering is left to
+// clients.
+type Comment struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"task_id"`
+	UserID    string     `json:"user_id"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type TaskDependency struct {
+	BlockerID string    `json:"blocker_id"`
+	BlockedID string    `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
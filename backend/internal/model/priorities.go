@@ -1,6 +1,9 @@
 package model
 
 type Priority struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-}
\ No newline at end of file
+	ID       int     `json:"id"`
+	UserID   *string `json:"user_id,omitempty"`
+	Name     string  `json:"name"`
+	Position int     `json:"position"`
+	Color    *string `json:"color,omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Reminder fires once, either at RemindAt or OffsetMinutes before the task's
+// due date. FireAt is the next time it will fire; snoozing moves it later.
+type Reminder struct {
+	ID            string     `json:"id"`
+	TaskID        string     `json:"task_id"`
+	UserID        string     `json:"user_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty"`
+	FireAt        *time.Time `json:"fire_at,omitempty"`
+	FiredAt       *time.Time `json:"fired_at,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Notification is an entry in a user's in-app inbox.
+type Notification struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	TaskID     *string    `json:"task_id,omitempty"`
+	ReminderID *string    `json:"reminder_id,omitempty"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+}
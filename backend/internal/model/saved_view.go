@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// SavedView is a named task query a user can run again later.
+type SavedView struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Query     ViewQuery  `json:"query"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ViewQuery is the stored form of a task list query. DueFrom and DueTo take
+// a date (YYYY-MM-DD) or a token relative to the day the view runs, such as
+// "today", "today+7d" or "today-2w".
+type ViewQuery struct {
+	StatusID             *int     `json:"status_id,omitempty"`
+	PriorityID           *int     `json:"priority_id,omitempty"`
+	CategoryID           *string  `json:"category_id,omitempty"`
+	IncludeSubcategories bool     `json:"include_subcategories,omitempty"`
+	DueFrom              string   `json:"due_from,omitempty"`
+	DueTo                string   `json:"due_to,omitempty"`
+	Overdue              bool     `json:"overdue,omitempty"`
+	Completed            *bool    `json:"completed,omitempty"`
+	Blocked              *bool    `json:"blocked,omitempty"`
+	Archived             bool     `json:"archived,omitempty"`
+	Tags                 []string `json:"tags,omitempty"`
+	TagMode              string   `json:"tag_mode,omitempty"`
+	Search               string   `json:"search,omitempty"`
+	Sort                 string   `json:"sort,omitempty"`
+	Order                string   `json:"order,omitempty"`
+	GroupBy              string   `json:"group_by,omitempty"`
+	Limit                int      `json:"limit,omitempty"`
+}
@@ -0,0 +1,19 @@
+package model
+
+const (
+	SearchKindTask     = "task"
+	SearchKindComment  = "comment"
+	SearchKindCategory = "category"
+)
+
+// SearchResult is one ranked hit. Title is the task title, the commented
+// task's title or the category name. Snippet is HTML-escaped text with the
+// matched words wrapped in <mark>.
+type SearchResult struct {
+	Kind    string  `json:"kind"`
+	ID      string  `json:"id"`
+	TaskID  *string `json:"task_id,omitempty"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
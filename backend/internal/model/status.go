@@ -1,6 +1,18 @@
 package model
 
 type Status struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-}
\ No newline at end of file
+	ID         int     `json:"id"`
+	UserID     *string `json:"user_id,omitempty"`
+	Name       string  `json:"name"`
+	Position   int     `json:"position"`
+	Color      *string `json:"color,omitempty"`
+	IsTerminal bool    `json:"is_terminal"`
+}
+
+type StatusTransition struct {
+	ID              int    `json:"id"`
+	UserID          string `json:"user_id"`
+	FromStatusID    int    `json:"from_status_id"`
+	ToStatusID      int    `json:"to_status_id"`
+	RequiresComment bool   `json:"requires_comment"`
+}
@@ -3,15 +3,44 @@ package model
 import "time"
 
 type Task struct {
-	ID          string     `json:"id"`
-	UserID      string     `json:"user_id"`
-	CategoryID  *string    `json:"category_id,omitempty"`
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	ParentID     *string    `json:"parent_id,omitempty"`
+	CategoryID   *string    `json:"category_id,omitempty"`
 	CategoryName *string    `json:"category_name,omitempty"`
-	StatusID    int        `json:"status_id"`
-	PriorityID  int        `json:"priority_id"`
-	Title       string     `json:"title"`
-	Description *string    `json:"description,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
-}
\ No newline at end of file
+	StatusID     int        `json:"status_id"`
+	PriorityID   int        `json:"priority_id"`
+	Title        string     `json:"title"`
+	Description  *string    `json:"description,omitempty"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	DueAllDay    bool       `json:"due_all_day"` // date-only, stored as midnight UTC
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	Blocked      bool       `json:"blocked"`
+	Tags         []string   `json:"tags"`
+	Recurrence   *string    `json:"recurrence,omitempty"`
+	SeriesID     *string    `json:"series_id,omitempty"`
+	Occurrence   int        `json:"occurrence,omitempty"`
+	// ScheduledFor is the date the recurrence rule produced for this
+	// occurrence; it differs from DueDate once a single occurrence is moved.
+	ScheduledFor *time.Time    `json:"-"`
+	Progress     *TaskProgress `json:"progress,omitempty"`
+	DeletedAt    *time.Time    `json:"deleted_at,omitempty"`
+	ArchivedAt   *time.Time    `json:"archived_at,omitempty"`
+	// Revision goes up on every write and is served as the task's ETag.
+	Revision int `json:"revision"`
+}
+
+// TaskProgress rolls up how many of a task's descendants are completed.
+type TaskProgress struct {
+	Total   int     `json:"total"`
+	Done    int     `json:"done"`
+	Percent float64 `json:"percent"`
+}
+
+type TaskNode struct {
+	Task
+	Depth    int         `json:"depth"`
+	Children []*TaskNode `json:"children"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TaskEventCreated    = "created"
+	TaskEventUpdated    = "updated"
+	TaskEventDeleted    = "deleted"
+	TaskEventRestored   = "restored"
+	TaskEventArchived   = "archived"
+	TaskEventUnarchived = "unarchived"
+)
+
+// TaskEvent is one entry of a task's audit trail. Changes maps each field
+// that differs to its JSON value before and after the mutation.
+type TaskEvent struct {
+	ID        int64                  `json:"id"`
+	TaskID    string                 `json:"task_id"`
+	UserID    string                 `json:"user_id"`
+	Action    string                 `json:"action"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type FieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
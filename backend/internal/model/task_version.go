@@ -0,0 +1,17 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TaskVersion is the state of a task's editable fields after one write.
+// RevertedFrom is set when the write restored an earlier version.
+type TaskVersion struct {
+	TaskID       string          `json:"task_id"`
+	Version      int             `json:"version"`
+	UserID       string          `json:"user_id"`
+	Snapshot     json.RawMessage `json:"snapshot"`
+	RevertedFrom *int            `json:"reverted_from,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
@@ -7,5 +7,7 @@ type User struct {
 	Name	string     `json:"name"`
 	Email	string     `json:"email"`
 	PasswordHash string    `json:"-"`
+	// Timezone is an IANA name; overdue and "today" are computed in it.
+	Timezone  string    `json:"timezone"`
 	CreatedAt time.Time `json:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type inbox struct {
+	repo repository.NotificationRepository
+}
+
+// NewInbox stores messages as in-app notifications.
+func NewInbox(repo repository.NotificationRepository) Notifier {
+	return &inbox{repo: repo}
+}
+
+func (n *inbox) Notify(ctx context.Context, m Message) error {
+	return n.repo.Create(ctx, &model.Notification{
+		UserID:     m.UserID,
+		TaskID:     &m.TaskID,
+		ReminderID: &m.ReminderID,
+		Title:      m.Title,
+		Body:       m.Body,
+	})
+}
@@ -0,0 +1,42 @@
+// Package notify delivers reminder notifications through pluggable channels.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Message is one reminder going out to a user.
+type Message struct {
+	UserID     string     `json:"user_id"`
+	TaskID     string     `json:"task_id"`
+	ReminderID string     `json:"reminder_id"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	DueAllDay  bool       `json:"due_all_day"`
+}
+
+// Notifier delivers a message. Delivery runs outside any transaction and is
+// at least once: when the outcome cannot be recorded afterwards, or a later
+// notifier in Multi fails, the message is delivered again on retry.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+type multi []Notifier
+
+// Multi delivers to each notifier in order and stops at the first error, so
+// the reminder is retried as a whole.
+func Multi(notifiers ...Notifier) Notifier {
+	return multi(notifiers)
+}
+
+func (m multi) Notify(ctx context.Context, msg Message) error {
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
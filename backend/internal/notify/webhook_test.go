@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookSignsBody(t *testing.T) {
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature-256")
+	}))
+	defer srv.Close()
+
+	m := Message{UserID: "u1", TaskID: "t1", ReminderID: "r1", Title: "Pay rent", Body: "Due Mon, Jan 5"}
+	if err := NewWebhook(srv.URL, "s3cret").Notify(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("X-Signature-256 = %q, want %q", signature, want)
+	}
+	var got Message
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != m {
+		t.Errorf("body = %+v, want %+v", got, m)
+	}
+}
+
+func TestWebhookWithoutSecretIsUnsigned(t *testing.T) {
+	signed := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header["X-Signature-256"]
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook(srv.URL, "").Notify(context.Background(), Message{}); err != nil {
+		t.Fatal(err)
+	}
+	if signed {
+		t.Error("X-Signature-256 sent without a secret")
+	}
+}
+
+func TestWebhookRejectsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook(srv.URL, "").Notify(context.Background(), Message{}); err == nil {
+		t.Error("expected an error for a 502 response")
+	}
+}
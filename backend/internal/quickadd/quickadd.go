@@ -0,0 +1,479 @@
+// Package quickadd turns a one-line task description such as
+//
+//	Pay rent every month on the 1st !high #finance
+//
+// into its parts: the title, a !priority marker, a #category hashtag, a due
+// date and a recurrence rule. It recognises:
+//
+//   - dates: today, tomorrow, monday..sunday, this fri, next fri, next week,
+//     next month, next year, in 3 days, in a week, 2026-11-05, nov 5, 5th nov,
+//     optionally after "on", "by" or "due";
+//   - recurrence: daily, weekly, monthly, yearly, every day, every weekday,
+//     every 2 weeks, every 2 weeks on fri, every other month,
+//     every mon and thu, every 15th, every month on the 1st,
+//     every month on the last day;
+//   - "!name" for the priority and "#name" (or "#a/b" for a subcategory) for
+//     the category.
+//
+// Weekday names always mean the next such day after today; "this fri"
+// includes today. Abbreviated weekdays need a word before them ("on sat").
+// Words in double quotes are kept in the title as typed, so
+// "\"Friday\" report" does not set a date. Only the first date and the
+// first recurrence phrase are used; later ones stay in the title.
+package quickadd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/recurrence"
+)
+
+// Result is what Parse recognised. DueDate is a day at midnight UTC, like
+// other due dates; it is set to the first occurrence when only a recurrence
+// was given.
+type Result struct {
+	Title      string     `json:"title"`
+	Priority   string     `json:"priority,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	Recurrence string     `json:"recurrence,omitempty"`
+}
+
+var (
+	ErrEmptyTitle    = errors.New("nothing is left for the title")
+	ErrTwoPriorities = errors.New("only one !priority can be given")
+	ErrTwoCategories = errors.New("only one #category can be given")
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "weds": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+type unit int
+
+const (
+	noUnit unit = iota
+	days
+	weeks
+	monthsUnit
+	years
+)
+
+func unitOf(word string) unit {
+	switch strings.TrimSuffix(word, "s") {
+	case "day":
+		return days
+	case "week":
+		return weeks
+	case "month":
+		return monthsUnit
+	case "year":
+		return years
+	}
+	return noUnit
+}
+
+// token is one whitespace-separated word, or a whole quoted segment.
+type token struct {
+	text   string // as typed
+	word   string // lower-cased without trailing punctuation; empty when quoted
+	quoted bool
+}
+
+func tokenize(input string) []token {
+	var toks []token
+	rs := []rune(input)
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+		if rs[i] == '"' {
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end < len(rs) {
+				if text := strings.TrimSpace(string(rs[i+1 : end])); text != "" {
+					toks = append(toks, token{text: text, quoted: true})
+				}
+				i = end + 1
+				continue
+			}
+		}
+		start := i
+		for i < len(rs) && !unicode.IsSpace(rs[i]) {
+			i++
+		}
+		text := string(rs[start:i])
+		toks = append(toks, token{text: text, word: strings.ToLower(strings.TrimRight(text, ",.;:?!"))})
+	}
+	return toks
+}
+
+type parser struct {
+	toks  []token
+	today time.Time
+}
+
+// word returns the matchable word at i, or "" past the end or for quoted text.
+func (p *parser) word(i int) string {
+	if i >= len(p.toks) {
+		return ""
+	}
+	return p.toks[i].word
+}
+
+// Parse reads a quick-add line. today is the user's current date at
+// midnight UTC; relative dates count from it.
+func Parse(input string, today time.Time) (*Result, error) {
+	p := &parser{toks: tokenize(input), today: today}
+	r := &Result{}
+	var rule *recurrence.Rule
+	var title []string
+
+	for i := 0; i < len(p.toks); {
+		t := p.toks[i]
+		if !t.quoted {
+			switch name := strings.TrimRight(t.text, ",.;:?"); {
+			case len(name) > 1 && name[0] == '!':
+				if r.Priority != "" {
+					return nil, ErrTwoPriorities
+				}
+				r.Priority = name[1:]
+				i++
+				continue
+			case len(name) > 1 && name[0] == '#':
+				if r.Category != "" {
+					return nil, ErrTwoCategories
+				}
+				r.Category = name[1:]
+				i++
+				continue
+			}
+			if rule == nil {
+				if n, rr := p.recurrence(i); n > 0 {
+					rule = rr
+					i += n
+					continue
+				}
+			}
+			if r.DueDate == nil {
+				if n, d := p.date(i); n > 0 {
+					r.DueDate = &d
+					i += n
+					continue
+				}
+			}
+		}
+		title = append(title, t.text)
+		i++
+	}
+
+	r.Title = strings.Join(title, " ")
+	if r.Title == "" {
+		return nil, ErrEmptyTitle
+	}
+	if rule != nil {
+		r.Recurrence = rule.String()
+		if r.DueDate == nil {
+			first := firstOccurrence(rule, today)
+			r.DueDate = &first
+		}
+	}
+	return r, nil
+}
+
+// date matches a date phrase at i and returns how many tokens it used.
+func (p *parser) date(i int) (int, time.Time) {
+	switch p.word(i) {
+	case "on", "by", "due":
+		if n, d := p.dateAt(i+1, true); n > 0 {
+			return n + 1, d
+		}
+		return 0, time.Time{}
+	}
+	return p.dateAt(i, false)
+}
+
+// dateAt matches a date phrase itself. A bare weekday must be spelled out
+// unless a preposition came before it, so "sun cream" keeps its "sun".
+func (p *parser) dateAt(i int, prefixed bool) (int, time.Time) {
+	w := p.word(i)
+	switch w {
+	case "today", "tonight":
+		return 1, p.today
+	case "tomorrow", "tmr", "tmrw":
+		return 1, p.today.AddDate(0, 0, 1)
+	case "this":
+		if wd, ok := weekdays[p.word(i+1)]; ok {
+			return 2, nextWeekday(p.today, wd, true)
+		}
+	case "next":
+		if wd, ok := weekdays[p.word(i+1)]; ok {
+			return 2, nextWeekday(p.today, wd, false)
+		}
+		switch unitOf(p.word(i + 1)) {
+		case weeks:
+			return 2, p.today.AddDate(0, 0, 7)
+		case monthsUnit:
+			return 2, addMonths(p.today, 1)
+		case years:
+			return 2, addMonths(p.today, 12)
+		}
+	case "in":
+		if n, ok := count(p.word(i + 1)); ok {
+			switch unitOf(p.word(i + 2)) {
+			case days:
+				return 3, p.today.AddDate(0, 0, n)
+			case weeks:
+				return 3, p.today.AddDate(0, 0, 7*n)
+			case monthsUnit:
+				return 3, addMonths(p.today, n)
+			case years:
+				return 3, addMonths(p.today, 12*n)
+			}
+		}
+	}
+
+	if wd, ok := weekdays[w]; ok && (prefixed || strings.HasSuffix(w, "day")) {
+		return 1, nextWeekday(p.today, wd, false)
+	}
+	if d, err := time.Parse("2006-01-02", w); err == nil {
+		return 1, d
+	}
+	if m, ok := months[w]; ok {
+		if day, ok := ordinal(p.word(i + 1)); ok {
+			if d, ok := nextMonthDay(p.today, m, day); ok {
+				return 2, d
+			}
+		}
+	}
+	if day, ok := ordinal(w); ok {
+		if m, ok := months[p.word(i+1)]; ok {
+			if d, ok := nextMonthDay(p.today, m, day); ok {
+				return 2, d
+			}
+		}
+	}
+	return 0, time.Time{}
+}
+
+// recurrence matches a recurrence phrase at i and returns how many tokens
+// it used.
+func (p *parser) recurrence(i int) (int, *recurrence.Rule) {
+	rule := &recurrence.Rule{Interval: 1}
+	n := 0
+	switch p.word(i) {
+	case "daily":
+		rule.Freq, n = recurrence.Daily, 1
+	case "weekly":
+		rule.Freq, n = recurrence.Weekly, 1
+	case "monthly":
+		rule.Freq, n = recurrence.Monthly, 1
+	case "yearly", "annually":
+		rule.Freq, n = recurrence.Yearly, 1
+	case "every":
+		n = p.every(i+1, rule)
+		if n == 0 {
+			return 0, nil
+		}
+		n++
+	default:
+		return 0, nil
+	}
+
+	// "every 2 weeks on fri" and "weekly on mon and thu" pin the days.
+	if rule.Freq == recurrence.Weekly && len(rule.ByDay) == 0 && p.word(i+n) == "on" {
+		if _, ok := weekdays[p.word(i+n+1)]; ok {
+			n += 1 + p.weekdayList(i+n+1, rule)
+		}
+	}
+	if rule.Freq == recurrence.Monthly && len(rule.ByMonthDay) == 0 {
+		if used, day := p.onMonthDay(i + n); used > 0 {
+			rule.ByMonthDay = []int{day}
+			n += used
+		}
+	}
+	return n, rule
+}
+
+// every matches what follows "every" and fills in rule.
+func (p *parser) every(i int, rule *recurrence.Rule) int {
+	w := p.word(i)
+	if w == "weekday" {
+		rule.Freq = recurrence.Weekly
+		rule.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return 1
+	}
+	if _, ok := weekdays[w]; ok {
+		return p.weekdayList(i, rule)
+	}
+	if day, ok := ordinal(w); ok && !isDigits(w) {
+		rule.Freq = recurrence.Monthly
+		rule.ByMonthDay = []int{day}
+		return 1
+	}
+
+	used := 0
+	if w == "other" {
+		rule.Interval, used = 2, 1
+	} else if n, ok := count(w); ok && w != "a" && w != "an" {
+		rule.Interval, used = n, 1
+	}
+	switch unitOf(p.word(i + used)) {
+	case days:
+		rule.Freq = recurrence.Daily
+	case weeks:
+		rule.Freq = recurrence.Weekly
+	case monthsUnit:
+		rule.Freq = recurrence.Monthly
+	case years:
+		rule.Freq = recurrence.Yearly
+	default:
+		return 0
+	}
+	return used + 1
+}
+
+// weekdayList reads "mon", "mon and thu" or "mon, wed & fri".
+func (p *parser) weekdayList(i int, rule *recurrence.Rule) int {
+	rule.Freq = recurrence.Weekly
+	n := 0
+	for {
+		wd, ok := weekdays[p.word(i+n)]
+		if !ok {
+			break
+		}
+		rule.ByDay = append(rule.ByDay, wd)
+		n++
+		if sep := p.word(i + n); sep == "and" || sep == "&" {
+			if _, ok := weekdays[p.word(i+n+1)]; ok {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// onMonthDay reads "on the 1st", "on the 15", "on 3rd" or "on the last day".
+func (p *parser) onMonthDay(i int) (int, int) {
+	if p.word(i) != "on" {
+		return 0, 0
+	}
+	n := 1
+	if p.word(i+n) == "the" {
+		n++
+	}
+	if p.word(i+n) == "last" && p.word(i+n+1) == "day" {
+		return n + 2, -1
+	}
+	if day, ok := ordinal(p.word(i + n)); ok {
+		return n + 1, day
+	}
+	return 0, 0
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// count reads a small positive number written as digits or a word.
+func count(w string) (int, bool) {
+	if n, ok := numberWords[w]; ok {
+		return n, true
+	}
+	if !isDigits(w) || len(w) > 3 {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(w)
+	return n, n > 0
+}
+
+// ordinal reads a day of the month: "1st", "22nd", "3rd", "15th" or "15".
+func ordinal(w string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if strings.HasSuffix(w, suffix) {
+			w = strings.TrimSuffix(w, suffix)
+			break
+		}
+	}
+	if !isDigits(w) || len(w) > 2 {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(w)
+	return n, n >= 1 && n <= 31
+}
+
+// nextWeekday is the first wd after today, or from today when inclusive.
+func nextWeekday(today time.Time, wd time.Weekday, inclusive bool) time.Time {
+	ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+	if ahead == 0 && !inclusive {
+		ahead = 7
+	}
+	return today.AddDate(0, 0, ahead)
+}
+
+// nextMonthDay is the next month/day on or after today; ok is false for a
+// day the month never has, such as Apr 31.
+func nextMonthDay(today time.Time, m time.Month, day int) (time.Time, bool) {
+	// Four years covers Feb 29.
+	for y := today.Year(); y <= today.Year()+4; y++ {
+		d := time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+		if d.Month() == m && !d.Before(today) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// addMonths clamps to the month's last day: Jan 31 + 1 month is Feb 28.
+func addMonths(d time.Time, n int) time.Time {
+	first := time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, d.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d.Day(), last)-1)
+}
+
+// firstOccurrence is the first day on or after today that rule produces,
+// used as the due date when only a recurrence was given.
+func firstOccurrence(rule *recurrence.Rule, today time.Time) time.Time {
+	if len(rule.ByDay) == 0 && len(rule.ByMonthDay) == 0 {
+		return today
+	}
+	// rule.Next looks strictly after its argument, so start a day early.
+	next, ok := rule.Next(today.AddDate(0, 0, -1), 1)
+	if !ok {
+		return today
+	}
+	return next
+}
@@ -0,0 +1,97 @@
+package quickadd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// today is a Wednesday. Parse works on the user's calendar date, which the
+// caller has already resolved in the user's timezone.
+var today = time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+
+func day(s string) *time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return &d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Result
+	}{
+		{"Buy milk", Result{Title: "Buy milk"}},
+		{"Call mom today !high", Result{Title: "Call mom", Priority: "high", DueDate: day("2025-01-15")}},
+		{"Buy milk tomorrow", Result{Title: "Buy milk", DueDate: day("2025-01-16")}},
+		{"Report friday #work", Result{Title: "Report", Category: "work", DueDate: day("2025-01-17")}},
+		{"Report on fri #work/q1", Result{Title: "Report", Category: "work/q1", DueDate: day("2025-01-17")}},
+		{"Pay by tomorrow", Result{Title: "Pay", DueDate: day("2025-01-16")}},
+		{"Dentist wednesday", Result{Title: "Dentist", DueDate: day("2025-01-22")}},
+		{"Dentist this wed", Result{Title: "Dentist", DueDate: day("2025-01-15")}},
+		{"Dentist next wed", Result{Title: "Dentist", DueDate: day("2025-01-22")}},
+		{"Plan next week", Result{Title: "Plan", DueDate: day("2025-01-22")}},
+		{"Plan next month", Result{Title: "Plan", DueDate: day("2025-02-15")}},
+		{"Plan next year", Result{Title: "Plan", DueDate: day("2026-01-15")}},
+		{"Renew in 3 days", Result{Title: "Renew", DueDate: day("2025-01-18")}},
+		{"Renew in a week", Result{Title: "Renew", DueDate: day("2025-01-22")}},
+		{"Renew in two months", Result{Title: "Renew", DueDate: day("2025-03-15")}},
+		{"Trip 2025-03-01", Result{Title: "Trip", DueDate: day("2025-03-01")}},
+		{"Trip mar 5", Result{Title: "Trip", DueDate: day("2025-03-05")}},
+		{"Trip 5th march", Result{Title: "Trip", DueDate: day("2025-03-05")}},
+		{"Trip jan 10", Result{Title: "Trip", DueDate: day("2026-01-10")}},
+		{"Lunch tomorrow and friday", Result{Title: "Lunch and friday", DueDate: day("2025-01-16")}},
+
+		// Abbreviated weekdays and quoted words stay in the title.
+		{"sun cream", Result{Title: "sun cream"}},
+		{`"Friday" report`, Result{Title: "Friday report"}},
+
+		{"Standup daily", Result{Title: "Standup", DueDate: day("2025-01-15"), Recurrence: "FREQ=DAILY"}},
+		{"Review every other month", Result{Title: "Review", DueDate: day("2025-01-15"), Recurrence: "FREQ=MONTHLY;INTERVAL=2"}},
+		{"Backup every weekday", Result{Title: "Backup", DueDate: day("2025-01-15"), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"Gym every mon and thu", Result{Title: "Gym", DueDate: day("2025-01-16"), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH"}},
+		{"Ship it every 2 weeks on friday", Result{Title: "Ship it", DueDate: day("2025-01-17"), Recurrence: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"}},
+		{"Sync weekly on mon & thu", Result{Title: "Sync", DueDate: day("2025-01-16"), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH"}},
+		{"Taxes every 15th", Result{Title: "Taxes", DueDate: day("2025-01-15"), Recurrence: "FREQ=MONTHLY;BYMONTHDAY=15"}},
+		{"Pay rent every month on the 1st !high #finance", Result{
+			Title: "Pay rent", Priority: "high", Category: "finance",
+			DueDate: day("2025-02-01"), Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1",
+		}},
+		{"Close books every month on the last day", Result{Title: "Close books", DueDate: day("2025-01-31"), Recurrence: "FREQ=MONTHLY;BYMONTHDAY=-1"}},
+		{"Water plants every 3 days starting tomorrow", Result{Title: "Water plants starting", DueDate: day("2025-01-16"), Recurrence: "FREQ=DAILY;INTERVAL=3"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input, today)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got.Title != tt.want.Title || got.Priority != tt.want.Priority ||
+			got.Category != tt.want.Category || got.Recurrence != tt.want.Recurrence {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, *got, tt.want)
+		}
+		if (got.DueDate == nil) != (tt.want.DueDate == nil) ||
+			got.DueDate != nil && !got.DueDate.Equal(*tt.want.DueDate) {
+			t.Errorf("Parse(%q) due = %v, want %v", tt.input, got.DueDate, tt.want.DueDate)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"", ErrEmptyTitle},
+		{"!high #work tomorrow", ErrEmptyTitle},
+		{"Report !high !low", ErrTwoPriorities},
+		{"Report #work #home", ErrTwoCategories},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.input, today); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Package recurrence implements the subset of RFC 5545 RRULE used for
+// recurring tasks: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// maxPeriods bounds the search for the next occurrence so that rules that
+// can never match (FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30 from February)
+// terminate.
+const maxPeriods = 1000
+
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("recurrence rule is empty")
+	}
+
+	r := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, fmt.Errorf("recurrence rule: malformed part %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("recurrence rule: %s given twice", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch f := Frequency(value); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("recurrence rule: unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, errors.New("recurrence rule: INTERVAL must be a positive number")
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				d, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("recurrence rule: invalid BYDAY value %q", code)
+				}
+				r.ByDay = append(r.ByDay, d)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("recurrence rule: invalid BYMONTHDAY value %q", v)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, errors.New("recurrence rule: COUNT must be a positive number")
+			}
+			r.Count = n
+		case "UNTIL":
+			var until time.Time
+			var err error
+			for _, layout := range untilLayouts {
+				if until, err = time.Parse(layout, value); err == nil {
+					break
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("recurrence rule: invalid UNTIL %q", value)
+			}
+			r.Until = &until
+		default:
+			return nil, fmt.Errorf("recurrence rule: unsupported part %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("recurrence rule: FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("recurrence rule: COUNT and UNTIL cannot be combined")
+	}
+	return r, nil
+}
+
+// String renders the rule in a canonical form so equal rules compare equal.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := append([]time.Weekday(nil), r.ByDay...)
+		sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+		codes := make([]string, 0, len(days))
+		for i, d := range days {
+			if i > 0 && days[i-1] == d {
+				continue
+			}
+			codes = append(codes, weekdayNames[d])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, d := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence that follows current, which is occurrence
+// number n (1-based) of the series. The time of day and location of current
+// carry over. ok is false once COUNT or UNTIL ends the series.
+func (r *Rule) Next(current time.Time, n int) (next time.Time, ok bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	for p := 0; p < maxPeriods; p++ {
+		for _, c := range r.candidates(current, p*r.Interval) {
+			if !c.After(current) {
+				continue
+			}
+			if r.Until != nil && c.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return c, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func day(t time.Time, year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (r *Rule) hasWeekday(d time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, w := range r.ByDay {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) hasMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := daysIn(t.Year(), t.Month())
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && last+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates lists, in order, the days of the period that lies offset
+// periods after the one containing current and that satisfy BYDAY and
+// BYMONTHDAY.
+func (r *Rule) candidates(current time.Time, offset int) []time.Time {
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		days = []time.Time{current.AddDate(0, 0, offset)}
+
+	case Weekly:
+		sinceMonday := (int(current.Weekday()) + 6) % 7
+		monday := current.AddDate(0, 0, offset*7-sinceMonday)
+		for i := 0; i < 7; i++ {
+			d := monday.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && d.Weekday() != current.Weekday() {
+				continue
+			}
+			days = append(days, d)
+		}
+
+	case Monthly:
+		first := time.Date(current.Year(), current.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		days = r.monthDays(current, first.Year(), first.Month())
+
+	case Yearly:
+		year := current.Year() + offset
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			days = r.monthDays(current, year, current.Month())
+			break
+		}
+		// Without BYMONTH, BYDAY and BYMONTHDAY range over the whole year.
+		for m := time.January; m <= time.December; m++ {
+			days = append(days, r.monthDays(current, year, m)...)
+		}
+	}
+
+	out := days[:0]
+	for _, d := range days {
+		if r.hasWeekday(d.Weekday()) && r.hasMonthDay(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// monthDays lists the candidate days within one month. Without BYDAY or
+// BYMONTHDAY the series repeats on current's day of month, and months that
+// are too short for it are skipped as RFC 5545 prescribes.
+func (r *Rule) monthDays(current time.Time, year int, month time.Month) []time.Time {
+	last := daysIn(year, month)
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if current.Day() > last {
+			return nil
+		}
+		return []time.Time{day(current, year, month, current.Day())}
+	}
+
+	days := make([]time.Time, 0, last)
+	for d := 1; d <= last; d++ {
+		days = append(days, day(current, year, month, d))
+	}
+	return days
+}
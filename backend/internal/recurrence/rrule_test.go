@@ -0,0 +1,143 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday.
+	wed := time.Date(2025, time.January, 15, 9, 30, 0, 0, time.UTC)
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		rule    string
+		current time.Time
+		want    time.Time
+	}{
+		{"FREQ=DAILY", wed, at(2025, 1, 16)},
+		{"FREQ=DAILY;INTERVAL=3", wed, at(2025, 1, 18)},
+		{"FREQ=DAILY;BYDAY=MO,FR", wed, at(2025, 1, 17)},
+		{"FREQ=DAILY;BYMONTHDAY=1", wed, at(2025, 2, 1)},
+
+		{"FREQ=WEEKLY", wed, at(2025, 1, 22)},
+		{"FREQ=WEEKLY;INTERVAL=2", wed, at(2025, 1, 29)},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", wed, at(2025, 1, 17)},
+		{"FREQ=WEEKLY;BYDAY=MO", wed, at(2025, 1, 20)},
+		// The Monday of the current week has passed; the next period is
+		// two weeks on.
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", wed, at(2025, 1, 27)},
+
+		{"FREQ=MONTHLY", wed, at(2025, 2, 15)},
+		{"FREQ=MONTHLY;INTERVAL=2", wed, at(2025, 3, 15)},
+		// February has no 31st, so it is skipped.
+		{"FREQ=MONTHLY", at(2025, 1, 31), at(2025, 3, 31)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", wed, at(2025, 1, 31)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", at(2025, 1, 31), at(2025, 2, 28)},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", wed, at(2025, 2, 1)},
+		{"FREQ=MONTHLY;BYDAY=MO", wed, at(2025, 1, 20)},
+		{"FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", wed, at(2025, 6, 13)},
+
+		{"FREQ=YEARLY", wed, at(2026, 1, 15)},
+		{"FREQ=YEARLY", at(2024, 2, 29), at(2028, 2, 29)},
+		{"FREQ=YEARLY;INTERVAL=2", wed, at(2027, 1, 15)},
+		{"FREQ=YEARLY;BYDAY=MO", wed, at(2025, 1, 20)},
+		// BYDAY covers the whole year, not just the starting month.
+		{"FREQ=YEARLY;BYDAY=MO", at(2025, 1, 27), at(2025, 2, 3)},
+		{"FREQ=YEARLY;BYDAY=MO", at(2025, 12, 30), at(2026, 1, 5)},
+		{"FREQ=YEARLY;INTERVAL=2;BYDAY=SU", at(2025, 12, 30), at(2027, 1, 3)},
+		{"FREQ=YEARLY;BYMONTHDAY=1", wed, at(2025, 2, 1)},
+		{"FREQ=YEARLY;BYDAY=FR;BYMONTHDAY=13", wed, at(2025, 6, 13)},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rule, err)
+		}
+		got, ok := r.Next(tt.current, 1)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%s from %s: got %s, %v; want %s", tt.rule, tt.current.Format("2006-01-02"),
+				got.Format("2006-01-02 15:04"), ok, tt.want.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func TestNextKeepsLocalTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	r, _ := Parse("FREQ=WEEKLY")
+	// Daylight saving time starts on 2025-03-09.
+	got, ok := r.Next(time.Date(2025, time.March, 5, 9, 0, 0, 0, ny), 1)
+	want := time.Date(2025, time.March, 12, 9, 0, 0, 0, ny)
+	if !ok || !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNextEnds(t *testing.T) {
+	start := time.Date(2025, time.January, 15, 9, 30, 0, 0, time.UTC)
+
+	r, _ := Parse("FREQ=DAILY;COUNT=3")
+	if _, ok := r.Next(start, 2); !ok {
+		t.Error("COUNT=3: occurrence 3 missing")
+	}
+	if _, ok := r.Next(start, 3); ok {
+		t.Error("COUNT=3: occurrence 4 produced")
+	}
+
+	r, _ = Parse("FREQ=DAILY;UNTIL=20250116T093000Z")
+	if _, ok := r.Next(start, 1); !ok {
+		t.Error("UNTIL: occurrence on the UNTIL instant missing")
+	}
+	if _, ok := r.Next(start.AddDate(0, 0, 1), 2); ok {
+		t.Error("UNTIL: occurrence after UNTIL produced")
+	}
+
+	r, _ = Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+	if _, ok := r.Next(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), 1); ok {
+		t.Error("a rule that never matches produced an occurrence")
+	}
+}
+
+func TestParse(t *testing.T) {
+	valid := map[string]string{
+		"FREQ=DAILY":                            "FREQ=DAILY",
+		"RRULE:freq=weekly;byday=fr,mo":         "FREQ=WEEKLY;BYDAY=MO,FR",
+		"FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=-1": "FREQ=MONTHLY;BYMONTHDAY=-1",
+		"FREQ=YEARLY;COUNT=5":                   "FREQ=YEARLY;COUNT=5",
+		"FREQ=DAILY;UNTIL=20250201":             "FREQ=DAILY;UNTIL=20250201T000000Z",
+	}
+	for in, want := range valid {
+		r, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", in, err)
+			continue
+		}
+		if got := r.String(); got != want {
+			t.Errorf("Parse(%q).String() = %q, want %q", in, got, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250201",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	}
+	for _, in := range invalid {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q): expected an error", in)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type AttachmentRepository interface {
+	ListByTask(ctx context.Context, taskID string) ([]*model.Attachment, error)
+	GetByID(ctx context.Context, attachmentID, taskID string) (*model.Attachment, error)
+	Create(ctx context.Context, a *model.Attachment) error
+	Delete(ctx context.Context, attachmentID, taskID string) error
+	// StorageKeys lists the blobs held by a task and, when withDescendants is
+	// set, by every task below it, trashed or not.
+	StorageKeys(ctx context.Context, taskID, userID string, withDescendants bool) ([]string, error)
+	// TrashedStorageKeys lists the blobs of tasks trashed before the cutoff,
+	// matching TaskRepository.PurgeTrashed.
+	TrashedStorageKeys(ctx context.Context, userID string, before time.Time) ([]string, error)
+}
+
+type attachmentRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepositoryPostgres{db: db}
+}
+
+const attachmentColumns = `
+	id, task_id, user_id, filename, content_type, size_bytes, checksum, storage_key, created_at`
+
+func scanAttachment(row rowScanner) (*model.Attachment, error) {
+	var a model.Attachment
+	err := row.Scan(&a.ID, &a.TaskID, &a.UserID, &a.Filename, &a.ContentType, &a.Size, &a.Checksum, &a.StorageKey, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *attachmentRepositoryPostgres) ListByTask(ctx context.Context, taskID string) ([]*model.Attachment, error) {
+	q := `SELECT` + attachmentColumns + ` FROM task_attachments WHERE task_id = $1 ORDER BY created_at, id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*model.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *attachmentRepositoryPostgres) GetByID(ctx context.Context, attachmentID, taskID string) (*model.Attachment, error) {
+	q := `SELECT` + attachmentColumns + ` FROM task_attachments WHERE id = $1 AND task_id = $2`
+	a, err := scanAttachment(conn(ctx, r.db).QueryRowContext(ctx, q, attachmentID, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+func (r *attachmentRepositoryPostgres) Create(ctx context.Context, a *model.Attachment) error {
+	q := `
+		INSERT INTO task_attachments (task_id, user_id, filename, content_type, size_bytes, checksum, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q,
+		a.TaskID, a.UserID, a.Filename, a.ContentType, a.Size, a.Checksum, a.StorageKey,
+	).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *attachmentRepositoryPostgres) Delete(ctx context.Context, attachmentID, taskID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM task_attachments WHERE id = $1 AND task_id = $2`, attachmentID, taskID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *attachmentRepositoryPostgres) StorageKeys(ctx context.Context, taskID, userID string, withDescendants bool) ([]string, error) {
+	q := subtreeCTE + `
+		SELECT a.storage_key
+		FROM task_attachments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE t.user_id = $2
+		  AND (t.id = $1 OR ($3 AND t.id IN (SELECT id FROM tree)))
+	`
+	return r.keys(ctx, q, taskID, userID, withDescendants)
+}
+
+func (r *attachmentRepositoryPostgres) TrashedStorageKeys(ctx context.Context, userID string, before time.Time) ([]string, error) {
+	q := `
+		SELECT a.storage_key
+		FROM task_attachments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE t.deleted_at IS NOT NULL AND t.deleted_at < $1
+		  AND ($2 = '' OR t.user_id::text = $2)
+	`
+	return r.keys(ctx, q, before, userID)
+}
+
+func (r *attachmentRepositoryPostgres) keys(ctx context.Context, q string, args ...any) ([]string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
@@ -3,16 +3,33 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 	
 	"github.com/liaa-aa/task-manager-project/backend/internal/model"
 )
 
 type CategoryRepository interface {
 	ExistOwned(ctx context.Context, categoryID, userID string) (bool, error)
-	GetByName(ctx context.Context, name string, userID string) (*model.Category, error)
+	// GetByName looks a name up among the children of parentID, or among
+	// top-level categories when parentID is nil.
+	GetByName(ctx context.Context, name string, userID string, parentID *string) (*model.Category, error)
+	GetByID(ctx context.Context, categoryID, userID string) (*model.Category, error)
 	Create(ctx context.Context, category *model.Category) error
-	ListByUser(ctx context.Context, userID string) ([]*model.Category, error)
+	Update(ctx context.Context, category *model.Category) error
+	ListByUser(ctx context.Context, userID string, includeArchived bool) ([]*model.Category, error)
+	// Reorder sets each category's position to its index in ids, starting at 1.
+	Reorder(ctx context.Context, userID string, ids []string) error
+	SetParent(ctx context.Context, categoryID, userID string, parentID *string) error
+	IsDescendant(ctx context.Context, ancestorID, categoryID, userID string) (bool, error)
+	ListChildren(ctx context.Context, categoryID, userID string) ([]*model.Category, error)
 	Delete(ctx context.Context, categoryID string) error
+	CountTasks(ctx context.Context, categoryID string) (int, error)
+
+	ListTrashed(ctx context.Context, userID string) ([]*model.Category, error)
+	GetTrashed(ctx context.Context, categoryID, userID string) (*model.Category, error)
+	Restore(ctx context.Context, categoryID, userID string) error
+	Purge(ctx context.Context, categoryID, userID string) error
+	PurgeTrashed(ctx context.Context, userID string, before time.Time) (int64, error)
 }
 
 type CategoryRepositoryPostgres struct {
@@ -23,63 +40,213 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 	return &CategoryRepositoryPostgres{db: db}
 }
 
-func (r *CategoryRepositoryPostgres) ExistOwned(ctx context.Context, categoryID, userID string) (bool, error) {
-	var ok bool
-	query := `
-	SELECT EXISTS (
-		SELECT 1 FROM categories WHERE id = $1 AND user_id = $2
-	)`
-	err := r.db.QueryRowContext(ctx, query, categoryID, userID).Scan(&ok)
+// categoryColumns selects the columns read by scanCategory from categories c.
+const categoryColumns = `
+	c.id, c.name, c.user_id, c.parent_id, c.color, c.icon, c.position, c.archived, c.created_at, c.deleted_at,
+	(SELECT COUNT(*) FROM tasks t WHERE t.category_id = c.id AND t.deleted_at IS NULL)`
+
+func scanCategory(row rowScanner) (*model.Category, error) {
+	var c model.Category
+	err := row.Scan(&c.ID, &c.Name, &c.UserID, &c.ParentID, &c.Color, &c.Icon, &c.Position, &c.Archived, &c.CreatedAt, &c.DeletedAt, &c.TaskCount)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return ok, nil
+	return &c, nil
 }
 
-func (r *CategoryRepositoryPostgres) GetByName(ctx context.Context, name string, userID string) (*model.Category, error) {
-	query := `SELECT id, name, user_id, created_at FROM categories WHERE name = $1 AND user_id = $2`
-	var c model.Category
-	err := r.db.QueryRowContext(ctx, query, name, userID).Scan(&c.ID, &c.Name, &c.UserID, &c.CreatedAt)
+func (r *CategoryRepositoryPostgres) getOne(ctx context.Context, where string, args ...any) (*model.Category, error) {
+	query := `SELECT` + categoryColumns + ` FROM categories c WHERE ` + where
+	c, err := scanCategory(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-
 	if err != nil {
 		return nil, err
 	}
-
-	return &c, nil
+	return c, nil
 }
 
-func (r *CategoryRepositoryPostgres) Create(ctx context.Context, category *model.Category) error {
-	query := `INSERT INTO categories (name, user_id) VALUES ($1, $2) RETURNING id, created_at`
-	return r.db.QueryRowContext(ctx, query, category.Name, category.UserID).Scan(&category.ID, &category.CreatedAt)
-}
-
-func (r *CategoryRepositoryPostgres) ListByUser(ctx context.Context, userID string) ([]*model.Category, error) {
-	query := `SELECT id, name, user_id, created_at FROM categories WHERE user_id = $1 ORDER BY name`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+func (r *CategoryRepositoryPostgres) list(ctx context.Context, where, order string, args ...any) ([]*model.Category, error) {
+	query := `SELECT` + categoryColumns + ` FROM categories c WHERE ` + where + ` ORDER BY ` + order
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var categories []*model.Category
+	categories := []*model.Category{}
 	for rows.Next() {
-		var c model.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.UserID, &c.CreatedAt)
+		c, err := scanCategory(rows)
 		if err != nil {
 			return nil, err
 		}
-		categories = append(categories, &c)
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (r *CategoryRepositoryPostgres) ExistOwned(ctx context.Context, categoryID, userID string) (bool, error) {
+	var ok bool
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM categories WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	)`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, categoryID, userID).Scan(&ok)
+	if err != nil {
+		return false, err
 	}
+	return ok, nil
+}
+
+func (r *CategoryRepositoryPostgres) GetByName(ctx context.Context, name string, userID string, parentID *string) (*model.Category, error) {
+	return r.getOne(ctx, `c.name = $1 AND c.user_id = $2 AND c.parent_id IS NOT DISTINCT FROM $3::uuid AND c.deleted_at IS NULL`,
+		name, userID, parentID)
+}
+
+func (r *CategoryRepositoryPostgres) GetByID(ctx context.Context, categoryID, userID string) (*model.Category, error) {
+	return r.getOne(ctx, `c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NULL`, categoryID, userID)
+}
 
-	return categories, nil
+// Create appends the category after its siblings unless a position is given.
+func (r *CategoryRepositoryPostgres) Create(ctx context.Context, category *model.Category) error {
+	query := `
+	INSERT INTO categories (name, user_id, parent_id, color, icon, position, archived)
+	VALUES ($1, $2, $3, $4, $5,
+	        CASE WHEN $6::int > 0 THEN $6::int
+	             ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM categories
+	                   WHERE user_id = $2 AND parent_id IS NOT DISTINCT FROM $3::uuid AND deleted_at IS NULL)
+	        END,
+	        $7)
+	RETURNING id, position, created_at`
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		category.Name, category.UserID, category.ParentID, category.Color, category.Icon, category.Position, category.Archived,
+	).Scan(&category.ID, &category.Position, &category.CreatedAt)
 }
 
+func (r *CategoryRepositoryPostgres) Update(ctx context.Context, category *model.Category) error {
+	query := `
+	UPDATE categories SET name = $1, color = $2, icon = $3, position = $4, archived = $5
+	WHERE id = $6 AND user_id = $7 AND deleted_at IS NULL
+	RETURNING created_at`
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		category.Name, category.Color, category.Icon, category.Position, category.Archived,
+		category.ID, category.UserID,
+	).Scan(&category.CreatedAt)
+}
 
+func (r *CategoryRepositoryPostgres) ListByUser(ctx context.Context, userID string, includeArchived bool) ([]*model.Category, error) {
+	return r.list(ctx, `c.user_id = $1 AND c.deleted_at IS NULL AND ($2 OR NOT c.archived)`,
+		`c.position, c.name, c.id`, userID, includeArchived)
+}
+
+func (r *CategoryRepositoryPostgres) Reorder(ctx context.Context, userID string, ids []string) error {
+	query := `
+	UPDATE categories c SET position = o.ord
+	FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, ord)
+	WHERE c.id = o.id AND c.user_id = $2 AND c.deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, ids, userID)
+	return err
+}
+
+func (r *CategoryRepositoryPostgres) SetParent(ctx context.Context, categoryID, userID string, parentID *string) error {
+	query := `UPDATE categories SET parent_id = $1 WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, parentID, categoryID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// categoryTreeCTE walks the live categories below $1 for user $2. UNION
+// rather than UNION ALL stops the walk should a cycle ever reach the data.
+const categoryTreeCTE = `
+	WITH RECURSIVE sub AS (
+		SELECT id FROM categories WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		UNION
+		SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id WHERE c.deleted_at IS NULL
+	)`
+
+func (r *CategoryRepositoryPostgres) IsDescendant(ctx context.Context, ancestorID, categoryID, userID string) (bool, error) {
+	var ok bool
+	query := categoryTreeCTE + `
+	SELECT EXISTS (SELECT 1 FROM sub WHERE id = $3)`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, ancestorID, userID, categoryID).Scan(&ok)
+	return ok, err
+}
+
+func (r *CategoryRepositoryPostgres) ListChildren(ctx context.Context, categoryID, userID string) ([]*model.Category, error) {
+	return r.list(ctx, `c.parent_id = $1 AND c.user_id = $2 AND c.deleted_at IS NULL`, `c.position, c.name, c.id`, categoryID, userID)
+}
+
+func (r *CategoryRepositoryPostgres) CountTasks(ctx context.Context, categoryID string) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM tasks WHERE category_id = $1 AND deleted_at IS NULL`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, categoryID).Scan(&n)
+	return n, err
+}
+
+// Delete moves the category to the trash. Tasks keep their category_id but
+// show no category until it is restored.
 func (r *CategoryRepositoryPostgres) Delete(ctx context.Context, categoryID string) error {
-	query := `DELETE FROM categories WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, categoryID)
+	query := `UPDATE categories SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, categoryID)
 	return err
 }
+
+func (r *CategoryRepositoryPostgres) ListTrashed(ctx context.Context, userID string) ([]*model.Category, error) {
+	return r.list(ctx, `c.user_id = $1 AND c.deleted_at IS NOT NULL`, `c.deleted_at DESC, c.id`, userID)
+}
+
+func (r *CategoryRepositoryPostgres) GetTrashed(ctx context.Context, categoryID, userID string) (*model.Category, error) {
+	return r.getOne(ctx, `c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NOT NULL`, categoryID, userID)
+}
+
+// Restore brings a trashed category back, at the top level if its parent
+// is no longer live.
+func (r *CategoryRepositoryPostgres) Restore(ctx context.Context, categoryID, userID string) error {
+	query := `
+	UPDATE categories c
+	SET deleted_at = NULL,
+	    parent_id = CASE WHEN EXISTS (
+	        SELECT 1 FROM categories p WHERE p.id = c.parent_id AND p.deleted_at IS NULL
+	    ) THEN c.parent_id END
+	WHERE c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, categoryID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CategoryRepositoryPostgres) Purge(ctx context.Context, categoryID, userID string) error {
+	query := `DELETE FROM categories WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, categoryID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CategoryRepositoryPostgres) PurgeTrashed(ctx context.Context, userID string, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM categories
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		  AND ($2 = '' OR user_id::text = $2)`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, before, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
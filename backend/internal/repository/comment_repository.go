@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type CommentRepository interface {
+	ListByTask(ctx context.Context, taskID string) ([]*model.Comment, error)
+	GetByID(ctx context.Context, commentID, taskID string) (*model.Comment, error)
+	Create(ctx context.Context, comment *model.Comment) error
+	Update(ctx context.Context, comment *model.Comment) error
+	Delete(ctx context.Context, commentID, userID string) error
+}
+
+type commentRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepositoryPostgres{db: db}
+}
+
+func (r *commentRepositoryPostgres) ListByTask(ctx context.Context, taskID string) ([]*model.Comment, error) {
+	q := `
+		SELECT id, task_id, user_id, body, created_at, updated_at
+		FROM task_comments
+		WHERE task_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*model.Comment{}
+	for rows.Next() {
+		var c model.Comment
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.UserID, &c.Body, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, &c)
+	}
+	return comments, rows.Err()
+}
+
+func (r *commentRepositoryPostgres) GetByID(ctx context.Context, commentID, taskID string) (*model.Comment, error) {
+	q := `
+		SELECT id, task_id, user_id, body, created_at, updated_at
+		FROM task_comments
+		WHERE id = $1 AND task_id = $2
+	`
+	var c model.Comment
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, commentID, taskID).
+		Scan(&c.ID, &c.TaskID, &c.UserID, &c.Body, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *commentRepositoryPostgres) Create(ctx context.Context, comment *model.Comment) error {
+	q := `
+		INSERT INTO task_comments (task_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, comment.TaskID, comment.UserID, comment.Body).
+		Scan(&comment.ID, &comment.CreatedAt)
+}
+
+func (r *commentRepositoryPostgres) Update(ctx context.Context, comment *model.Comment) error {
+	q := `
+		UPDATE task_comments SET body = $1, updated_at = now()
+		WHERE id = $2 AND user_id = $3
+		RETURNING created_at, updated_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, comment.Body, comment.ID, comment.UserID).
+		Scan(&comment.CreatedAt, &comment.UpdatedAt)
+}
+
+func (r *commentRepositoryPostgres) Delete(ctx context.Context, commentID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM task_comments WHERE id = $1 AND user_id = $2`, commentID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
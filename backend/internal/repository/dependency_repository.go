@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type DependencyRepository interface {
+	Add(ctx context.Context, userID string, d *model.TaskDependency) error
+	Remove(ctx context.Context, userID, blockerID, blockedID string) error
+	ListByUser(ctx context.Context, userID string) ([]*model.TaskDependency, error)
+	// ListAllByUser also returns the edges ListByUser hides because they
+	// touch a trashed task.
+	ListAllByUser(ctx context.Context, userID string) ([]*model.TaskDependency, error)
+	ListForTask(ctx context.Context, taskID, userID string) ([]*model.TaskDependency, error)
+	OpenBlockers(ctx context.Context, taskID, userID string) ([]string, error)
+	// LockGraph serialises changes to the user's dependencies until the
+	// surrounding transaction ends.
+	LockGraph(ctx context.Context, userID string) error
+}
+
+type dependencyRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewDependencyRepository(db *sql.DB) DependencyRepository {
+	return &dependencyRepositoryPostgres{db: db}
+}
+
+func (r *dependencyRepositoryPostgres) Add(ctx context.Context, userID string, d *model.TaskDependency) error {
+	q := `
+		INSERT INTO task_dependencies (blocker_id, blocked_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET created_at = task_dependencies.created_at
+		RETURNING created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, d.BlockerID, d.BlockedID, userID).Scan(&d.CreatedAt)
+}
+
+// LockGraph takes a transaction-scoped advisory lock per user. Row locks on
+// the existing dependencies would not do: two adds that insert the first
+// edges in each direction have no rows to contend on.
+func (r *dependencyRepositoryPostgres) LockGraph(ctx context.Context, userID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('task_dependencies:' || $1))`, userID)
+	return err
+}
+
+func (r *dependencyRepositoryPostgres) Remove(ctx context.Context, userID, blockerID, blockedID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM task_dependencies WHERE blocker_id=$1 AND blocked_id=$2 AND user_id=$3`,
+		blockerID, blockedID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanDependencies(rows *sql.Rows) ([]*model.TaskDependency, error) {
+	defer rows.Close()
+
+	out := []*model.TaskDependency{}
+	for rows.Next() {
+		var d model.TaskDependency
+		if err := rows.Scan(&d.BlockerID, &d.BlockedID, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &d)
+	}
+	return out, rows.Err()
+}
+
+// liveDependency hides edges to trashed tasks; they come back on restore.
+const liveDependency = `NOT EXISTS (
+			SELECT 1 FROM tasks x
+			WHERE x.id IN (d.blocker_id, d.blocked_id) AND x.deleted_at IS NOT NULL
+		)`
+
+func (r *dependencyRepositoryPostgres) ListByUser(ctx context.Context, userID string) ([]*model.TaskDependency, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT d.blocker_id, d.blocked_id, d.created_at
+		FROM task_dependencies d
+		WHERE d.user_id = $1 AND `+liveDependency+`
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDependencies(rows)
+}
+
+func (r *dependencyRepositoryPostgres) ListAllByUser(ctx context.Context, userID string) ([]*model.TaskDependency, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT d.blocker_id, d.blocked_id, d.created_at
+		FROM task_dependencies d
+		WHERE d.user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDependencies(rows)
+}
+
+func (r *dependencyRepositoryPostgres) ListForTask(ctx context.Context, taskID, userID string) ([]*model.TaskDependency, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT d.blocker_id, d.blocked_id, d.created_at
+		FROM task_dependencies d
+		WHERE d.user_id = $2 AND (d.blocker_id = $1 OR d.blocked_id = $1) AND `+liveDependency+`
+		ORDER BY d.created_at
+	`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDependencies(rows)
+}
+
+func (r *dependencyRepositoryPostgres) OpenBlockers(ctx context.Context, taskID, userID string) ([]string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT d.blocker_id
+		FROM task_dependencies d
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE d.blocked_id = $1 AND d.user_id = $2 AND b.completed_at IS NULL AND b.deleted_at IS NULL
+		ORDER BY d.created_at
+	`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *model.Notification) error
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, limit int) ([]*model.Notification, error)
+	MarkRead(ctx context.Context, notificationID, userID string) error
+}
+
+type notificationRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepositoryPostgres{db: db}
+}
+
+func (r *notificationRepositoryPostgres) Create(ctx context.Context, n *model.Notification) error {
+	q := `
+		INSERT INTO notifications (user_id, task_id, reminder_id, title, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, n.UserID, n.TaskID, n.ReminderID, n.Title, n.Body).
+		Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *notificationRepositoryPostgres) ListByUser(ctx context.Context, userID string, unreadOnly bool, limit int) ([]*model.Notification, error) {
+	q := `
+		SELECT id, user_id, task_id, reminder_id, title, body, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.TaskID, &n.ReminderID, &n.Title, &n.Body, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
+
+// MarkRead is idempotent: reading a read notification keeps its read_at.
+func (r *notificationRepositoryPostgres) MarkRead(ctx context.Context, notificationID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`,
+		notificationID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+// DueReminder is a reminder claimed for delivery, with what the message
+// needs from its task and user.
+type DueReminder struct {
+	model.Reminder
+	TaskTitle string
+	DueDate   *time.Time
+	DueAllDay bool
+	Timezone  string
+	// Closed is set when the task is completed, archived or in the trash;
+	// such reminders are retired without being sent.
+	Closed bool
+}
+
+type ReminderRepository interface {
+	ListByTask(ctx context.Context, taskID string) ([]*model.Reminder, error)
+	GetByID(ctx context.Context, reminderID, taskID string) (*model.Reminder, error)
+	Create(ctx context.Context, reminder *model.Reminder) error
+	Delete(ctx context.Context, reminderID, taskID string) error
+	Snooze(ctx context.Context, reminderID, taskID string, until time.Time) error
+	RescheduleOffsets(ctx context.Context, taskID string, base *time.Time) error
+	CopyOffsets(ctx context.Context, fromTaskID, toTaskID string, base *time.Time) error
+	ClaimDue(ctx context.Context, now, lease time.Time, limit int) ([]*DueReminder, error)
+	MarkFired(ctx context.Context, reminderID string, lease time.Time) error
+	MarkFailed(ctx context.Context, reminderID string, lease time.Time, message string, retryAt *time.Time) error
+}
+
+type reminderRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewReminderRepository(db *sql.DB) ReminderRepository {
+	return &reminderRepositoryPostgres{db: db}
+}
+
+const reminderColumns = `r.id, r.task_id, r.user_id, r.remind_at, r.offset_minutes, r.fire_at, r.fired_at,
+		r.attempts, r.last_error, r.created_at`
+
+func reminderDest(rm *model.Reminder) []any {
+	return []any{
+		&rm.ID, &rm.TaskID, &rm.UserID, &rm.RemindAt, &rm.OffsetMinutes, &rm.FireAt, &rm.FiredAt,
+		&rm.Attempts, &rm.LastError, &rm.CreatedAt,
+	}
+}
+
+func (r *reminderRepositoryPostgres) ListByTask(ctx context.Context, taskID string) ([]*model.Reminder, error) {
+	q := `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		WHERE r.task_id = $1
+		ORDER BY r.fire_at NULLS LAST, r.created_at, r.id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []*model.Reminder{}
+	for rows.Next() {
+		var rm model.Reminder
+		if err := rows.Scan(reminderDest(&rm)...); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &rm)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *reminderRepositoryPostgres) GetByID(ctx context.Context, reminderID, taskID string) (*model.Reminder, error) {
+	q := `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		WHERE r.id = $1 AND r.task_id = $2
+	`
+	var rm model.Reminder
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, reminderID, taskID).Scan(reminderDest(&rm)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (r *reminderRepositoryPostgres) Create(ctx context.Context, reminder *model.Reminder) error {
+	q := `
+		INSERT INTO reminders (task_id, user_id, remind_at, offset_minutes, fire_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, attempts, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q,
+		reminder.TaskID, reminder.UserID, reminder.RemindAt, reminder.OffsetMinutes, reminder.FireAt,
+	).Scan(&reminder.ID, &reminder.Attempts, &reminder.CreatedAt)
+}
+
+func (r *reminderRepositoryPostgres) Delete(ctx context.Context, reminderID, taskID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM reminders WHERE id = $1 AND task_id = $2`, reminderID, taskID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Snooze re-arms the reminder, fired or not, to go off at until.
+func (r *reminderRepositoryPostgres) Snooze(ctx context.Context, reminderID, taskID string, until time.Time) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE reminders SET fire_at = $3, fired_at = NULL, attempts = 0, last_error = NULL
+		WHERE id = $1 AND task_id = $2`, reminderID, taskID, until)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RescheduleOffsets moves the task's offset reminders to offset minutes
+// before base, the moment the task is due; a nil base parks them. Those
+// that end up in the future are armed again even if they already fired.
+func (r *reminderRepositoryPostgres) RescheduleOffsets(ctx context.Context, taskID string, base *time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE reminders
+		SET fire_at = $2::timestamptz - make_interval(mins => offset_minutes),
+		    fired_at = CASE WHEN $2::timestamptz - make_interval(mins => offset_minutes) > now() THEN NULL ELSE fired_at END,
+		    attempts = 0, last_error = NULL
+		WHERE task_id = $1 AND offset_minutes IS NOT NULL`, taskID, base)
+	return err
+}
+
+// CopyOffsets gives a new occurrence of a recurring task the offset
+// reminders of the previous one, counted from base.
+func (r *reminderRepositoryPostgres) CopyOffsets(ctx context.Context, fromTaskID, toTaskID string, base *time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO reminders (task_id, user_id, offset_minutes, fire_at)
+		SELECT $2, user_id, offset_minutes, $3::timestamptz - make_interval(mins => offset_minutes)
+		FROM reminders
+		WHERE task_id = $1 AND offset_minutes IS NOT NULL`, fromTaskID, toTaskID, base)
+	return err
+}
+
+// ClaimDue leases up to limit reminders due at now by moving their fire_at
+// to lease, and returns them. It is a single statement: SKIP LOCKED lets
+// other server instances claim different rows at the same time, and the
+// row locks last only as long as the statement. A reminder whose outcome is
+// never recorded, because the process died mid-delivery, comes due again
+// when the lease runs out.
+func (r *reminderRepositoryPostgres) ClaimDue(ctx context.Context, now, lease time.Time, limit int) ([]*DueReminder, error) {
+	q := `
+		WITH due AS (
+			SELECT id FROM reminders
+			WHERE fired_at IS NULL AND fire_at <= $1
+			ORDER BY fire_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE reminders SET fire_at = $2
+			FROM due WHERE reminders.id = due.id
+			RETURNING reminders.*
+		)
+		SELECT ` + reminderColumns + `,
+		       t.title, t.due_date, t.due_all_day, u.timezone,
+		       (t.completed_at IS NOT NULL OR t.archived_at IS NOT NULL OR t.deleted_at IS NOT NULL)
+		FROM claimed r
+		JOIN tasks t ON t.id = r.task_id
+		JOIN users u ON u.id = r.user_id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, now, lease, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*DueReminder
+	for rows.Next() {
+		var d DueReminder
+		dest := append(reminderDest(&d.Reminder), &d.TaskTitle, &d.DueDate, &d.DueAllDay, &d.Timezone, &d.Closed)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		due = append(due, &d)
+	}
+	return due, rows.Err()
+}
+
+// MarkFired records a delivered reminder. Like MarkFailed it only touches a
+// reminder still holding the given lease, so a snooze or due date change made
+// during delivery is not overwritten.
+func (r *reminderRepositoryPostgres) MarkFired(ctx context.Context, reminderID string, lease time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE reminders SET fired_at = now(), last_error = NULL WHERE id = $1 AND fire_at = $2`,
+		reminderID, lease)
+	return err
+}
+
+// MarkFailed records a failed delivery. A nil retryAt gives up and retires
+// the reminder; otherwise it fires again at retryAt.
+func (r *reminderRepositoryPostgres) MarkFailed(ctx context.Context, reminderID string, lease time.Time, message string, retryAt *time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE reminders
+		SET attempts = attempts + 1, last_error = $3,
+		    fire_at = COALESCE($4::timestamptz, fire_at),
+		    fired_at = CASE WHEN $4::timestamptz IS NULL THEN now() END
+		WHERE id = $1 AND fire_at = $2`, reminderID, lease, message, retryAt)
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type SavedViewRepository interface {
+	ListByUser(ctx context.Context, userID string) ([]*model.SavedView, error)
+	GetByID(ctx context.Context, viewID, userID string) (*model.SavedView, error)
+	GetByName(ctx context.Context, name string, userID string) (*model.SavedView, error)
+	Create(ctx context.Context, view *model.SavedView) error
+	Update(ctx context.Context, view *model.SavedView) error
+	Delete(ctx context.Context, viewID, userID string) error
+}
+
+type savedViewRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewSavedViewRepository(db *sql.DB) SavedViewRepository {
+	return &savedViewRepositoryPostgres{db: db}
+}
+
+const savedViewColumns = `SELECT id, user_id, name, query, created_at, updated_at FROM saved_views WHERE `
+
+func scanSavedView(row rowScanner) (*model.SavedView, error) {
+	var v model.SavedView
+	var query []byte
+	if err := row.Scan(&v.ID, &v.UserID, &v.Name, &query, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(query, &v.Query); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *savedViewRepositoryPostgres) ListByUser(ctx context.Context, userID string) ([]*model.SavedView, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, savedViewColumns+`user_id = $1 ORDER BY lower(name), id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := []*model.SavedView{}
+	for rows.Next() {
+		v, err := scanSavedView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+func (r *savedViewRepositoryPostgres) getOne(ctx context.Context, where string, args ...any) (*model.SavedView, error) {
+	v, err := scanSavedView(conn(ctx, r.db).QueryRowContext(ctx, savedViewColumns+where, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *savedViewRepositoryPostgres) GetByID(ctx context.Context, viewID, userID string) (*model.SavedView, error) {
+	return r.getOne(ctx, "id = $1 AND user_id = $2", viewID, userID)
+}
+
+func (r *savedViewRepositoryPostgres) GetByName(ctx context.Context, name string, userID string) (*model.SavedView, error) {
+	return r.getOne(ctx, "lower(name) = lower($1) AND user_id = $2", name, userID)
+}
+
+func (r *savedViewRepositoryPostgres) Create(ctx context.Context, view *model.SavedView) error {
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return err
+	}
+	q := `
+		INSERT INTO saved_views (user_id, name, query)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, view.UserID, view.Name, query).Scan(&view.ID, &view.CreatedAt)
+}
+
+func (r *savedViewRepositoryPostgres) Update(ctx context.Context, view *model.SavedView) error {
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return err
+	}
+	q := `
+		UPDATE saved_views SET name = $1, query = $2, updated_at = now()
+		WHERE id = $3 AND user_id = $4
+		RETURNING created_at, updated_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, view.Name, query, view.ID, view.UserID).
+		Scan(&view.CreatedAt, &view.UpdatedAt)
+}
+
+func (r *savedViewRepositoryPostgres) Delete(ctx context.Context, viewID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1 AND user_id = $2`, viewID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+// SearchQuery is a full-text search. TSQuery uses to_tsquery syntax and is
+// built by the caller from sanitised words. Filter narrows tasks and the
+// tasks that comments belong to; categories only match on their name.
+type SearchQuery struct {
+	UserID  string
+	TSQuery string
+	Kinds   []string
+	Filter  TaskFilter
+	Limit   int
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, q SearchQuery) ([]*model.SearchResult, error)
+}
+
+type searchRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) SearchRepository {
+	return &searchRepositoryPostgres{db: db}
+}
+
+// headline highlights matches of tsq in text. The text is HTML-escaped
+// first so that only the <mark> tags in the snippet are markup.
+func headline(text, tsq string) string {
+	escaped := fmt.Sprintf(`replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`, text)
+	return fmt.Sprintf(`ts_headline('simple', %s, %s,
+			'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2')`, escaped, tsq)
+}
+
+func (r *searchRepositoryPostgres) Search(ctx context.Context, sq SearchQuery) ([]*model.SearchResult, error) {
+	q := &taskQuery{}
+	userArg := q.arg(sq.UserID)
+	q.add("t.user_id = " + userArg)
+	q.add("t.deleted_at IS NULL")
+	q.applyFilter(sq.Filter)
+	taskWhere := strings.Join(q.where, " AND ")
+	tsq := "to_tsquery('simple', " + q.arg(sq.TSQuery) + ")"
+
+	var parts []string
+	for _, kind := range sq.Kinds {
+		switch kind {
+		case model.SearchKindTask:
+			parts = append(parts, `
+		SELECT 'task' AS kind, t.id, t.id AS task_id, t.title,
+		       `+headline("concat_ws(' — ', t.title, t.description)", tsq)+` AS snippet,
+		       ts_rank(t.search_vector, `+tsq+`) AS rank
+		FROM tasks t
+		WHERE `+taskWhere+` AND t.search_vector @@ `+tsq)
+		case model.SearchKindComment:
+			parts = append(parts, `
+		SELECT 'comment' AS kind, cm.id, t.id AS task_id, t.title,
+		       `+headline("cm.body", tsq)+` AS snippet,
+		       ts_rank(cm.search_vector, `+tsq+`) AS rank
+		FROM task_comments cm JOIN tasks t ON t.id = cm.task_id
+		WHERE `+taskWhere+` AND cm.search_vector @@ `+tsq)
+		case model.SearchKindCategory:
+			parts = append(parts, `
+		SELECT 'category' AS kind, c.id, NULL::uuid AS task_id, c.name AS title,
+		       `+headline("c.name", tsq)+` AS snippet,
+		       ts_rank(to_tsvector('simple', c.name), `+tsq+`) AS rank
+		FROM categories c
+		WHERE c.user_id = `+userArg+` AND c.deleted_at IS NULL
+		  AND to_tsvector('simple', c.name) @@ `+tsq)
+		}
+	}
+	if len(parts) == 0 {
+		return []*model.SearchResult{}, nil
+	}
+
+	query := strings.Join(parts, "\n\t\tUNION ALL") + `
+		ORDER BY rank DESC, kind, id
+		LIMIT ` + q.arg(sq.Limit)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*model.SearchResult{}
+	for rows.Next() {
+		var res model.SearchResult
+		var taskID sql.NullString
+		if err := rows.Scan(&res.Kind, &res.ID, &taskID, &res.Title, &res.Snippet, &res.Rank); err != nil {
+			return nil, err
+		}
+		if taskID.Valid {
+			v := taskID.String
+			res.TaskID = &v
+		}
+		results = append(results, &res)
+	}
+	return results, rows.Err()
+}
@@ -3,11 +3,31 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
 )
 
 type StatusPrioritiesRepository interface {
 	StatusExist(ctx context.Context, statusID int, userID string) (bool, error)
 	PrioritiesExist(ctx context.Context, priorityID int, userID string) (bool, error)
+
+	ListStatuses(ctx context.Context, userID string) ([]*model.Status, error)
+	GetStatus(ctx context.Context, statusID int, userID string) (*model.Status, error)
+	CreateStatus(ctx context.Context, s *model.Status) error
+	UpdateStatus(ctx context.Context, s *model.Status) error
+	DeleteStatus(ctx context.Context, statusID int, userID string) error
+	StatusNameTaken(ctx context.Context, name string, userID string, excludeID int) (bool, error)
+	StatusInUse(ctx context.Context, statusID int) (bool, error)
+
+	ListPriorities(ctx context.Context, userID string) ([]*model.Priority, error)
+	GetPriority(ctx context.Context, priorityID int, userID string) (*model.Priority, error)
+	CreatePriority(ctx context.Context, p *model.Priority) error
+	UpdatePriority(ctx context.Context, p *model.Priority) error
+	DeletePriority(ctx context.Context, priorityID int, userID string) error
+	PriorityNameTaken(ctx context.Context, name string, userID string, excludeID int) (bool, error)
+	PriorityInUse(ctx context.Context, priorityID int) (bool, error)
 }
 
 type StatusPrioritiesRepositoryPostgres struct {
@@ -18,22 +38,278 @@ func NewStatusPrioritiesRepositoryPostgres(db *sql.DB) StatusPrioritiesRepositor
 	return &StatusPrioritiesRepositoryPostgres{db: db}
 }
 
-func (r *StatusPrioritiesRepositoryPostgres) StatusExist(ctx context.Context, statusID int, userID string) (bool, error) {
+// Statuses and priorities share one table layout: global defaults have a
+// NULL user_id and every user sees them next to their own rows. The helpers
+// below take the table name and are only ever called with the two constants.
+const (
+	statusTable   = "statuses"
+	priorityTable = "priorities"
+)
+
+type levelRow struct {
+	ID         int
+	UserID     *string
+	Name       string
+	Position   int
+	Color      *string
+	IsTerminal bool
+}
+
+// terminalColumn is the expression read and written for levelRow.IsTerminal;
+// only statuses carry the flag.
+func terminalColumn(table string) string {
+	if table == statusTable {
+		return "is_terminal"
+	}
+	return "false"
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) exist(ctx context.Context, table string, id int, userID string) (bool, error) {
 	var ok bool
 	query := `
 	SELECT EXISTS (
-		SELECT 1 FROM statuses WHERE id = $1 
+		SELECT 1 FROM ` + table + ` WHERE id = $1 AND (user_id IS NULL OR user_id = $2)
 	)`
-	err := r.db.QueryRowContext(ctx, query, statusID).Scan(&ok)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, userID).Scan(&ok)
 	return ok, err
 }
 
-func (r *StatusPrioritiesRepositoryPostgres) PrioritiesExist(ctx context.Context, priorityID int, userID string) (bool, error) {
+func scanLevel(row rowScanner) (*levelRow, error) {
+	var l levelRow
+	var uid sql.NullString
+	var color sql.NullString
+	if err := row.Scan(&l.ID, &uid, &l.Name, &l.Position, &color, &l.IsTerminal); err != nil {
+		return nil, err
+	}
+	if uid.Valid {
+		v := uid.String
+		l.UserID = &v
+	}
+	if color.Valid {
+		v := color.String
+		l.Color = &v
+	}
+	return &l, nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) list(ctx context.Context, table, userID string) ([]*levelRow, error) {
+	query := `
+	SELECT id, user_id, name, position, color, ` + terminalColumn(table) + `
+	FROM ` + table + `
+	WHERE user_id IS NULL OR user_id = $1
+	ORDER BY position, id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*levelRow
+	for rows.Next() {
+		l, err := scanLevel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) get(ctx context.Context, table string, id int, userID string) (*levelRow, error) {
+	query := `
+	SELECT id, user_id, name, position, color, ` + terminalColumn(table) + `
+	FROM ` + table + `
+	WHERE id = $1 AND (user_id IS NULL OR user_id = $2)`
+	l, err := scanLevel(conn(ctx, r.db).QueryRowContext(ctx, query, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return l, err
+}
+
+// extraColumns lists the columns beyond the shared layout that a table
+// stores, with the values of l written to them.
+func extraColumns(table string, l *levelRow) ([]string, []any) {
+	if table == statusTable {
+		return []string{"is_terminal"}, []any{l.IsTerminal}
+	}
+	return nil, nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) create(ctx context.Context, table string, l *levelRow) error {
+	columns := "user_id, name, position, color"
+	values := `$1, $2, COALESCE(NULLIF($3, 0), (
+		SELECT COALESCE(MAX(position), 0) + 1 FROM ` + table + ` WHERE user_id IS NULL OR user_id = $1
+	)), $4`
+	args := []any{l.UserID, l.Name, l.Position, l.Color}
+	extra, extraArgs := extraColumns(table, l)
+	for i, col := range extra {
+		args = append(args, extraArgs[i])
+		columns += ", " + col
+		values += fmt.Sprintf(", $%d", len(args))
+	}
+
+	query := `
+	INSERT INTO ` + table + ` (` + columns + `)
+	VALUES (` + values + `)
+	RETURNING id, position`
+	return conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.Position)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) update(ctx context.Context, table string, l *levelRow) error {
+	set := "name = $1, position = $2, color = $3"
+	args := []any{l.Name, l.Position, l.Color}
+	extra, extraArgs := extraColumns(table, l)
+	for i, col := range extra {
+		args = append(args, extraArgs[i])
+		set += fmt.Sprintf(", %s = $%d", col, len(args))
+	}
+	args = append(args, l.ID, l.UserID)
+
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET %s
+	WHERE id = $%d AND user_id = $%d`, table, set, len(args)-1, len(args))
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) delete(ctx context.Context, table string, id int, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM `+table+` WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) nameTaken(ctx context.Context, table, name, userID string, excludeID int) (bool, error) {
 	var ok bool
 	query := `
 	SELECT EXISTS (
-		SELECT 1 FROM priorities WHERE id = $1 
+		SELECT 1 FROM ` + table + `
+		WHERE lower(name) = lower($1) AND (user_id IS NULL OR user_id = $2) AND id <> $3
 	)`
-	err := r.db.QueryRowContext(ctx, query, priorityID).Scan(&ok)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, name, userID, excludeID).Scan(&ok)
+	return ok, err
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) StatusExist(ctx context.Context, statusID int, userID string) (bool, error) {
+	return r.exist(ctx, statusTable, statusID, userID)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) PrioritiesExist(ctx context.Context, priorityID int, userID string) (bool, error) {
+	return r.exist(ctx, priorityTable, priorityID, userID)
+}
+
+func toStatus(l *levelRow) *model.Status {
+	return &model.Status{ID: l.ID, UserID: l.UserID, Name: l.Name, Position: l.Position, Color: l.Color, IsTerminal: l.IsTerminal}
+}
+
+func toPriority(l *levelRow) *model.Priority {
+	return &model.Priority{ID: l.ID, UserID: l.UserID, Name: l.Name, Position: l.Position, Color: l.Color}
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) ListStatuses(ctx context.Context, userID string) ([]*model.Status, error) {
+	rows, err := r.list(ctx, statusTable, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.Status, 0, len(rows))
+	for _, l := range rows {
+		out = append(out, toStatus(l))
+	}
+	return out, nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) GetStatus(ctx context.Context, statusID int, userID string) (*model.Status, error) {
+	l, err := r.get(ctx, statusTable, statusID, userID)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	return toStatus(l), nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) CreateStatus(ctx context.Context, s *model.Status) error {
+	l := &levelRow{UserID: s.UserID, Name: s.Name, Position: s.Position, Color: s.Color, IsTerminal: s.IsTerminal}
+	if err := r.create(ctx, statusTable, l); err != nil {
+		return err
+	}
+	s.ID, s.Position = l.ID, l.Position
+	return nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) UpdateStatus(ctx context.Context, s *model.Status) error {
+	return r.update(ctx, statusTable, &levelRow{ID: s.ID, UserID: s.UserID, Name: s.Name, Position: s.Position, Color: s.Color, IsTerminal: s.IsTerminal})
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) DeleteStatus(ctx context.Context, statusID int, userID string) error {
+	return r.delete(ctx, statusTable, statusID, userID)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) StatusNameTaken(ctx context.Context, name string, userID string, excludeID int) (bool, error) {
+	return r.nameTaken(ctx, statusTable, name, userID, excludeID)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) StatusInUse(ctx context.Context, statusID int) (bool, error) {
+	var ok bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE status_id = $1)`, statusID).Scan(&ok)
+	return ok, err
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) ListPriorities(ctx context.Context, userID string) ([]*model.Priority, error) {
+	rows, err := r.list(ctx, priorityTable, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.Priority, 0, len(rows))
+	for _, l := range rows {
+		out = append(out, toPriority(l))
+	}
+	return out, nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) GetPriority(ctx context.Context, priorityID int, userID string) (*model.Priority, error) {
+	l, err := r.get(ctx, priorityTable, priorityID, userID)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	return toPriority(l), nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) CreatePriority(ctx context.Context, p *model.Priority) error {
+	l := &levelRow{UserID: p.UserID, Name: p.Name, Position: p.Position, Color: p.Color}
+	if err := r.create(ctx, priorityTable, l); err != nil {
+		return err
+	}
+	p.ID, p.Position = l.ID, l.Position
+	return nil
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) UpdatePriority(ctx context.Context, p *model.Priority) error {
+	return r.update(ctx, priorityTable, &levelRow{ID: p.ID, UserID: p.UserID, Name: p.Name, Position: p.Position, Color: p.Color})
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) DeletePriority(ctx context.Context, priorityID int, userID string) error {
+	return r.delete(ctx, priorityTable, priorityID, userID)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) PriorityNameTaken(ctx context.Context, name string, userID string, excludeID int) (bool, error) {
+	return r.nameTaken(ctx, priorityTable, name, userID, excludeID)
+}
+
+func (r *StatusPrioritiesRepositoryPostgres) PriorityInUse(ctx context.Context, priorityID int) (bool, error) {
+	var ok bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE priority_id = $1)`, priorityID).Scan(&ok)
 	return ok, err
 }
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type TagRepository interface {
+	ListByUser(ctx context.Context, userID string) ([]*model.Tag, error)
+	GetByID(ctx context.Context, tagID, userID string) (*model.Tag, error)
+	GetByName(ctx context.Context, name string, userID string) (*model.Tag, error)
+	Create(ctx context.Context, tag *model.Tag) error
+	Ensure(ctx context.Context, tag *model.Tag) error
+	Rename(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, tagID, userID string) error
+	SetTaskTags(ctx context.Context, taskID string, tagIDs []string) error
+}
+
+type tagRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepositoryPostgres{db: db}
+}
+
+func (r *tagRepositoryPostgres) ListByUser(ctx context.Context, userID string) ([]*model.Tag, error) {
+	q := `
+		SELECT tg.id, tg.user_id, tg.name, tg.created_at, COUNT(t.id)
+		FROM tags tg
+		LEFT JOIN task_tags tt ON tt.tag_id = tg.id
+		LEFT JOIN tasks t ON t.id = tt.task_id AND t.deleted_at IS NULL
+		WHERE tg.user_id = $1
+		GROUP BY tg.id
+		ORDER BY lower(tg.name)
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*model.Tag{}
+	for rows.Next() {
+		var t model.Tag
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt, &t.TaskCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
+	}
+	return tags, rows.Err()
+}
+
+func (r *tagRepositoryPostgres) getOne(ctx context.Context, where string, args ...any) (*model.Tag, error) {
+	q := `
+		SELECT tg.id, tg.user_id, tg.name, tg.created_at,
+		       (SELECT COUNT(*) FROM task_tags tt JOIN tasks t ON t.id = tt.task_id
+		        WHERE tt.tag_id = tg.id AND t.deleted_at IS NULL)
+		FROM tags tg
+		WHERE ` + where
+	var t model.Tag
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, args...).Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt, &t.TaskCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tagRepositoryPostgres) GetByID(ctx context.Context, tagID, userID string) (*model.Tag, error) {
+	return r.getOne(ctx, "tg.id = $1 AND tg.user_id = $2", tagID, userID)
+}
+
+func (r *tagRepositoryPostgres) GetByName(ctx context.Context, name string, userID string) (*model.Tag, error) {
+	return r.getOne(ctx, "lower(tg.name) = lower($1) AND tg.user_id = $2", name, userID)
+}
+
+func (r *tagRepositoryPostgres) Create(ctx context.Context, tag *model.Tag) error {
+	q := `INSERT INTO tags (name, user_id) VALUES ($1, $2) RETURNING id, created_at`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, tag.Name, tag.UserID).Scan(&tag.ID, &tag.CreatedAt)
+}
+
+// Ensure creates the tag unless one with the same name (case-insensitively)
+// exists, and loads the stored row either way. It is safe to call inside a
+// transaction because it never fails on a duplicate name.
+func (r *tagRepositoryPostgres) Ensure(ctx context.Context, tag *model.Tag) error {
+	q := `
+		INSERT INTO tags (name, user_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, lower(name)) DO UPDATE SET name = tags.name
+		RETURNING id, name, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, tag.Name, tag.UserID).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+}
+
+func (r *tagRepositoryPostgres) Rename(ctx context.Context, tag *model.Tag) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3`, tag.Name, tag.ID, tag.UserID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *tagRepositoryPostgres) Delete(ctx context.Context, tagID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, tagID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetTaskTags replaces the tags on a task with tagIDs.
+func (r *tagRepositoryPostgres) SetTaskTags(ctx context.Context, taskID string, tagIDs []string) error {
+	return withTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		if _, err := db.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID); err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO task_tags (task_id, tag_id)
+			SELECT $1, unnest($2::uuid[])
+			ON CONFLICT DO NOTHING
+		`, taskID, tagIDs)
+		return err
+	})
+}
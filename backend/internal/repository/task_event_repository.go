@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type TaskEventRepository interface {
+	Record(ctx context.Context, e *model.TaskEvent) error
+	ListByTask(ctx context.Context, taskID, userID string) ([]*model.TaskEvent, error)
+}
+
+type taskEventRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewTaskEventRepository(db *sql.DB) TaskEventRepository {
+	return &taskEventRepositoryPostgres{db: db}
+}
+
+func (r *taskEventRepositoryPostgres) Record(ctx context.Context, e *model.TaskEvent) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+	q := `
+		INSERT INTO task_events (task_id, user_id, action, changes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, e.TaskID, e.UserID, e.Action, changes).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *taskEventRepositoryPostgres) ListByTask(ctx context.Context, taskID, userID string) ([]*model.TaskEvent, error) {
+	q := `
+		SELECT id, task_id, user_id, action, changes, created_at
+		FROM task_events
+		WHERE task_id = $1 AND user_id = $2
+		ORDER BY id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*model.TaskEvent{}
+	for rows.Next() {
+		var e model.TaskEvent
+		var changes []byte
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Action, &changes, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/taskquery"
+)
+
+// applyQuery adds one condition per query-language term. Values are always
+// bound as arguments; relative dates resolve against today and days begin
+// in loc. A negated term wraps its condition in NOT COALESCE(..., false) so
+// that NULL columns, such as a missing category, count as not matching
+// rather than dropping the row.
+func (q *taskQuery) applyQuery(query *taskquery.Query, today time.Time, loc *time.Location) {
+	for _, term := range query.Terms {
+		cond := q.queryTerm(term, today, loc)
+		if taskquery.Base(term).Negated {
+			cond = "NOT COALESCE((" + cond + "), false)"
+		}
+		q.add(cond)
+	}
+}
+
+func (q *taskQuery) queryTerm(term taskquery.Term, today time.Time, loc *time.Location) string {
+	switch t := term.(type) {
+	case *taskquery.StatusTerm:
+		name := strings.ToLower(t.Name)
+		cond := "lower(st.name) = " + q.arg(name)
+		switch name {
+		case "open":
+			cond += " OR NOT st.is_terminal"
+		case "done", "closed":
+			cond += " OR st.is_terminal"
+		}
+		return "EXISTS (SELECT 1 FROM statuses st WHERE st.id = t.status_id AND (" + cond + "))"
+
+	case *taskquery.PriorityTerm:
+		name := q.arg(strings.ToLower(t.Name))
+		if t.Op == taskquery.OpEq {
+			return "lower(p.name) = " + name
+		}
+		// The user's own priority wins over a built-in one of the same name.
+		return "p.position " + string(t.Op) + ` (
+			SELECT pr.position FROM priorities pr
+			WHERE lower(pr.name) = ` + name + ` AND (pr.user_id IS NULL OR pr.user_id = t.user_id)
+			ORDER BY pr.user_id NULLS LAST LIMIT 1)`
+
+	case *taskquery.DateTerm:
+		if t.None {
+			return "t.due_date IS NULL"
+		}
+		before := func(day time.Time) string {
+			if t.Field == "created" {
+				return "t.created_at < " + q.arg(localMidnight(day, loc))
+			}
+			return q.dueBefore(day, loc)
+		}
+		// A date covers its whole day, so due<=D and due:D include D itself.
+		day := t.Date.On(today)
+		next := day.AddDate(0, 0, 1)
+		switch t.Op {
+		case taskquery.OpLt:
+			return before(day)
+		case taskquery.OpLe:
+			return before(next)
+		case taskquery.OpGt:
+			return "NOT " + before(next)
+		case taskquery.OpGe:
+			return "NOT " + before(day)
+		default:
+			return "NOT " + before(day) + " AND " + before(next)
+		}
+
+	case *taskquery.CategoryTerm:
+		if t.None {
+			return "c.id IS NULL"
+		}
+		return "lower(c.name) = " + q.arg(strings.ToLower(t.Name))
+
+	case *taskquery.TagTerm:
+		return `EXISTS (SELECT 1 FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.task_id = t.id AND lower(tg.name) = ` + q.arg(strings.ToLower(t.Name)) + ")"
+
+	case *taskquery.IsTerm:
+		switch t.State {
+		case "open":
+			return "t.completed_at IS NULL"
+		case "done":
+			return "t.completed_at IS NOT NULL"
+		case "overdue":
+			return q.overdue(today)
+		case "blocked":
+			return strings.TrimSuffix(blockedExpr, " AS blocked")
+		case "archived":
+			return "t.archived_at IS NOT NULL"
+		default:
+			return "t.recurrence IS NOT NULL"
+		}
+
+	case *taskquery.TitleTerm:
+		return "t.title ILIKE '%' || " + q.arg(escapeLike(t.Text)) + " || '%'"
+
+	case *taskquery.TextTerm:
+		pattern := "'%' || " + q.arg(escapeLike(t.Text)) + " || '%'"
+		return "(t.title ILIKE " + pattern + " OR COALESCE(t.description, '') ILIKE " + pattern + ")"
+	}
+	return "true"
+}
+
+func localMidnight(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
+
+// dueBefore is true when a task falls due before day starts in loc. All-day
+// due dates are midnight UTC of their calendar day and compare with day
+// itself; timed ones with the instant loc's day begins. It is NULL for tasks
+// without a due date.
+func (q *taskQuery) dueBefore(day time.Time, loc *time.Location) string {
+	return "(CASE WHEN t.due_all_day THEN t.due_date < " + q.arg(day) +
+		" ELSE t.due_date < " + q.arg(localMidnight(day, loc)) + " END)"
+}
+
+// overdue matches open tasks whose all-day date is before today or whose
+// due time has passed.
+func (q *taskQuery) overdue(today time.Time) string {
+	return "t.completed_at IS NULL AND (CASE WHEN t.due_all_day THEN t.due_date < " + q.arg(today) +
+		" ELSE t.due_date < now() END)"
+}
@@ -3,17 +3,137 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/taskquery"
 )
 
 type TaskRepository interface {
-	ListByUser(ctx context.Context, userID string) ([]*model.Task, error)
+	ListByUser(ctx context.Context, userID string, filter TaskFilter) (*TaskPage, error)
 	GetByID(ctx context.Context, taskID, userID string) (*model.Task, error)
 	Create(ctx context.Context, t *model.Task) error
 	Update(ctx context.Context, t *model.Task) error
-	Delete(ctx context.Context, taskID, userID string) error
+	// Patch writes only the given columns of t; see ChangedTaskColumns. Like
+	// Update it only applies at revision t.Revision.
+	Patch(ctx context.Context, t *model.Task, columns []string) error
+	Delete(ctx context.Context, taskID, userID string, mode ChildDeleteMode) error
+	ListDescendants(ctx context.Context, taskID, userID string, maxDepth int) ([]*model.TaskNode, error)
+	IsDescendant(ctx context.Context, ancestorID, taskID, userID string) (bool, error)
+	Progress(ctx context.Context, taskID, userID string) (*model.TaskProgress, error)
+	OccurrenceExists(ctx context.Context, seriesID string, occurrence int, userID string) (bool, error)
+	// UpdateSeries returns the occurrences it changed as they were before.
+	UpdateSeries(ctx context.Context, t *model.Task) ([]*model.Task, error)
+
+	// MoveCategory points every live task in category from at category to
+	// (nil to uncategorize) and returns the tasks it changed as they were
+	// before.
+	MoveCategory(ctx context.Context, userID, from string, to *string) ([]*model.Task, error)
+	// DeleteByCategory moves the tasks in a category, with their subtasks, to
+	// the trash and returns the IDs of every task it trashed.
+	DeleteByCategory(ctx context.Context, categoryID, userID string) ([]string, error)
+
+	ListTrashed(ctx context.Context, userID string) ([]*model.Task, error)
+	Restore(ctx context.Context, taskID, userID string) error
+	Purge(ctx context.Context, taskID, userID string) error
+	// PurgeTrashed permanently deletes tasks trashed before the cutoff, for
+	// one user or, when userID is empty, for everyone.
+	PurgeTrashed(ctx context.Context, userID string, before time.Time) (int64, error)
+}
+
+// ChildDeleteMode decides what happens to a task's subtasks when it is moved
+// to the trash.
+type ChildDeleteMode string
+
+const (
+	DeleteChildrenCascade  ChildDeleteMode = "cascade"
+	DeleteChildrenReparent ChildDeleteMode = "reparent"
+)
+
+type TaskFilter struct {
+	StatusID   *int
+	PriorityID *int
+	CategoryID *string
+	// IncludeSubcategories widens CategoryID to its descendant categories.
+	IncludeSubcategories bool
+	DueFrom              *time.Time
+	DueTo                *time.Time
+	Overdue              bool
+	Completed            *bool
+	Blocked              *bool
+	// Archived lists archived tasks instead of the active ones.
+	Archived bool
+	Tags     []string
+	TagMode  string
+	Search   string
+	// Query holds parsed query-language terms.
+	Query *taskquery.Query
+	// Location is the user's timezone and Today their current date at
+	// midnight UTC; overdue, due ranges and query dates use them.
+	Location *time.Location
+	Today    time.Time
+	Sort     string
+	Order    string
+	Limit    int
+	Cursor   *TaskCursor
+}
+
+type TaskPage struct {
+	Tasks      []*model.Task `json:"tasks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// TaskCursor marks the last row of a page: the sort key and order it was
+// produced with, that row's sort value rendered as text by Postgres, and its id.
+type TaskCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (c TaskCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeTaskCursor(s string) (*TaskCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c TaskCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort == "" || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type taskSortColumn struct {
+	expr string
+	cast string
+}
+
+var taskSortColumns = map[string]taskSortColumn{
+	"created_at": {expr: "t.created_at", cast: "timestamptz"},
+	"updated_at": {expr: "COALESCE(t.updated_at, t.created_at)", cast: "timestamptz"},
+	"due_date":   {expr: "COALESCE(t.due_date, 'infinity'::timestamptz)", cast: "timestamptz"},
+	"priority":   {expr: "p.position", cast: "int"},
+	"title":      {expr: "t.title", cast: "text"},
+}
+
+func IsTaskSortKey(key string) bool {
+	_, ok := taskSortColumns[key]
+	return ok
 }
 
 type taskRepositoryPostgres struct {
@@ -24,84 +144,58 @@ func NewTaskRepository(db *sql.DB) TaskRepository {
 	return &taskRepositoryPostgres{db: db}
 }
 
-func (r *taskRepositoryPostgres) ListByUser(ctx context.Context, userID string) ([]*model.Task, error) {
-	q := `
-		SELECT t.id, t.user_id, t.category_id, c.name AS category_name,
-		       t.status_id, t.priority_id, t.title, t.description, t.due_date, t.created_at, t.updated_at
-		FROM tasks t
-		LEFT JOIN categories c ON t.category_id = c.id
-		WHERE t.user_id=$1
-		ORDER BY t.created_at DESC
-	`
-	rows, err := r.db.QueryContext(ctx, q, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+const taskColumns = `
+		t.id, t.user_id, t.category_id, c.name AS category_name,
+		t.status_id, t.priority_id, t.title, t.description, t.due_date, t.due_all_day, t.created_at, t.updated_at,
+		t.completed_at, t.parent_id, t.recurrence, t.series_id, t.occurrence, t.scheduled_for,
+		COALESCE((
+			SELECT json_agg(tg.name ORDER BY lower(tg.name))
+			FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.task_id = t.id
+		), '[]') AS tags,
+		t.deleted_at, t.archived_at, t.revision,
+		` + blockedExpr
 
-	var out []*model.Task
-	for rows.Next() {
-		var t model.Task
-		var cat sql.NullString
-		var categoryName sql.NullString
-		var desc sql.NullString
-		var due sql.NullTime
-		var upd sql.NullTime
-
-		if err := rows.Scan(
-			&t.ID, &t.UserID, &cat, &categoryName, &t.StatusID, &t.PriorityID, &t.Title, &desc, &due, &t.CreatedAt, &upd,
-		); err != nil {
-			return nil, err
-		}
+// blockedExpr is true while any task blocking t is still open.
+const blockedExpr = `EXISTS (
+			SELECT 1 FROM task_dependencies d
+			JOIN tasks b ON b.id = d.blocker_id
+			WHERE d.blocked_id = t.id AND b.completed_at IS NULL AND b.deleted_at IS NULL
+		) AS blocked`
 
-		if cat.Valid {
-			v := cat.String
-			t.CategoryID = &v
-		}
-		if categoryName.Valid {
-			v := categoryName.String
-			t.CategoryName = &v
-		}
-		if desc.Valid {
-			v := desc.String
-			t.Description = &v
-		}
-		if due.Valid {
-			v := due.Time
-			t.DueDate = &v
-		}
-		if upd.Valid {
-			v := upd.Time
-			t.UpdatedAt = &v
-		}
+const taskFrom = `
+		FROM tasks t
+		LEFT JOIN categories c ON t.category_id = c.id AND c.deleted_at IS NULL
+		JOIN priorities p ON t.priority_id = p.id`
 
-		out = append(out, &t)
-	}
-	return out, rows.Err()
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (r *taskRepositoryPostgres) GetByID(ctx context.Context, taskID, userID string) (*model.Task, error) {
-	q := `
-		SELECT t.id, t.user_id, t.category_id, c.name AS category_name,
-		       t.status_id, t.priority_id, t.title, t.description, t.due_date, t.created_at, t.updated_at
-		FROM tasks t
-		LEFT JOIN categories c ON t.category_id = c.id
-		WHERE t.id=$1 AND t.user_id=$2
-	`
+func scanTask(row rowScanner, extra ...any) (*model.Task, error) {
 	var t model.Task
 	var cat sql.NullString
 	var categoryName sql.NullString
 	var desc sql.NullString
 	var due sql.NullTime
 	var upd sql.NullTime
+	var completed sql.NullTime
+	var parent sql.NullString
+	var recurrence sql.NullString
+	var series sql.NullString
+	var scheduled sql.NullTime
+	var tags []byte
+	var deleted sql.NullTime
+	var archived sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, q, taskID, userID).Scan(
-		&t.ID, &t.UserID, &cat, &categoryName, &t.StatusID, &t.PriorityID, &t.Title, &desc, &due, &t.CreatedAt, &upd,
-	)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, nil
+	dest := []any{
+		&t.ID, &t.UserID, &cat, &categoryName, &t.StatusID, &t.PriorityID, &t.Title, &desc, &due, &t.DueAllDay, &t.CreatedAt, &upd,
+		&completed, &parent, &recurrence, &series, &t.Occurrence, &scheduled, &tags, &deleted, &archived, &t.Revision, &t.Blocked,
 	}
-	if err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tags, &t.Tags); err != nil {
 		return nil, err
 	}
 
@@ -125,33 +219,231 @@ func (r *taskRepositoryPostgres) GetByID(ctx context.Context, taskID, userID str
 		v := upd.Time
 		t.UpdatedAt = &v
 	}
-
+	if completed.Valid {
+		v := completed.Time
+		t.CompletedAt = &v
+	}
+	if parent.Valid {
+		v := parent.String
+		t.ParentID = &v
+	}
+	if recurrence.Valid {
+		v := recurrence.String
+		t.Recurrence = &v
+	}
+	if series.Valid {
+		v := series.String
+		t.SeriesID = &v
+	}
+	if scheduled.Valid {
+		v := scheduled.Time
+		t.ScheduledFor = &v
+	}
+	if deleted.Valid {
+		v := deleted.Time
+		t.DeletedAt = &v
+	}
+	if archived.Valid {
+		v := archived.Time
+		t.ArchivedAt = &v
+	}
 	return &t, nil
 }
 
+// taskQuery accumulates WHERE conditions and their positional arguments.
+type taskQuery struct {
+	where []string
+	args  []any
+}
+
+func (q *taskQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+func (q *taskQuery) add(cond string) {
+	q.where = append(q.where, cond)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (q *taskQuery) applyFilter(f TaskFilter) {
+	if f.StatusID != nil {
+		q.add("t.status_id = " + q.arg(*f.StatusID))
+	}
+	if f.PriorityID != nil {
+		q.add("t.priority_id = " + q.arg(*f.PriorityID))
+	}
+	if f.CategoryID != nil && f.IncludeSubcategories {
+		id := q.arg(*f.CategoryID)
+		q.add(`t.category_id IN (
+			WITH RECURSIVE sub AS (
+				SELECT id FROM categories WHERE id = ` + id + `::uuid
+				UNION
+				SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id WHERE c.deleted_at IS NULL
+			)
+			SELECT id FROM sub)`)
+	} else if f.CategoryID != nil {
+		q.add("t.category_id = " + q.arg(*f.CategoryID))
+	}
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if f.DueFrom != nil {
+		q.add("NOT " + q.dueBefore(*f.DueFrom, loc))
+	}
+	if f.DueTo != nil {
+		q.add(q.dueBefore(f.DueTo.AddDate(0, 0, 1), loc))
+	}
+	if f.Archived {
+		q.add("t.archived_at IS NOT NULL")
+	} else {
+		q.add("t.archived_at IS NULL")
+	}
+	if f.Completed != nil {
+		if *f.Completed {
+			q.add("t.completed_at IS NOT NULL")
+		} else {
+			q.add("t.completed_at IS NULL")
+		}
+	}
+	if f.Overdue {
+		q.add(q.overdue(f.Today))
+	}
+	if f.Blocked != nil {
+		cond := strings.TrimSuffix(blockedExpr, " AS blocked")
+		if !*f.Blocked {
+			cond = "NOT " + cond
+		}
+		q.add(cond)
+	}
+	if len(f.Tags) > 0 {
+		names := make([]string, 0, len(f.Tags))
+		seen := map[string]bool{}
+		for _, tag := range f.Tags {
+			n := strings.ToLower(tag)
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+		matching := `
+			FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.task_id = t.id AND lower(tg.name) = ANY(` + q.arg(names) + `::text[])`
+		if f.TagMode == "all" {
+			q.add("(SELECT COUNT(DISTINCT lower(tg.name))" + matching + ") = " + q.arg(len(names)))
+		} else {
+			q.add("EXISTS (SELECT 1" + matching + ")")
+		}
+	}
+	if f.Search != "" {
+		q.add("t.title ILIKE '%' || " + q.arg(escapeLike(f.Search)) + " || '%'")
+	}
+	if f.Query != nil {
+		q.applyQuery(f.Query, f.Today, loc)
+	}
+}
+
+func (r *taskRepositoryPostgres) ListByUser(ctx context.Context, userID string, filter TaskFilter) (*TaskPage, error) {
+	col, ok := taskSortColumns[filter.Sort]
+	if !ok {
+		col = taskSortColumns["created_at"]
+		filter.Sort = "created_at"
+	}
+	dir, cmp := "DESC", "<"
+	if filter.Order == "asc" {
+		dir, cmp = "ASC", ">"
+	}
+
+	q := &taskQuery{}
+	q.add("t.user_id = " + q.arg(userID))
+	q.add("t.deleted_at IS NULL")
+	q.applyFilter(filter)
+	if filter.Cursor != nil {
+		q.add(fmt.Sprintf("(%s, t.id) %s (%s::%s, %s)",
+			col.expr, cmp, q.arg(filter.Cursor.Value), col.cast, q.arg(filter.Cursor.ID)))
+	}
+
+	query := "SELECT" + taskColumns + ", (" + col.expr + ")::text AS sort_value" + taskFrom +
+		"\n\t\tWHERE " + strings.Join(q.where, " AND ") +
+		fmt.Sprintf("\n\t\tORDER BY %s %s, t.id %s", col.expr, dir, dir)
+	if filter.Limit > 0 {
+		query += "\n\t\tLIMIT " + q.arg(filter.Limit+1)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &TaskPage{Tasks: []*model.Task{}}
+	var last TaskCursor
+	for rows.Next() {
+		var sortValue string
+		t, err := scanTask(rows, &sortValue)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Limit > 0 && len(page.Tasks) == filter.Limit {
+			page.NextCursor = last.Encode()
+			break
+		}
+		page.Tasks = append(page.Tasks, t)
+		last = TaskCursor{Sort: filter.Sort, Order: filter.Order, Value: sortValue, ID: t.ID}
+	}
+	return page, rows.Err()
+}
+
+func (r *taskRepositoryPostgres) GetByID(ctx context.Context, taskID, userID string) (*model.Task, error) {
+	q := "SELECT" + taskColumns + taskFrom + `
+		WHERE t.id=$1 AND t.user_id=$2 AND t.deleted_at IS NULL`
+	t, err := scanTask(conn(ctx, r.db).QueryRowContext(ctx, q, taskID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
 func (r *taskRepositoryPostgres) Create(ctx context.Context, t *model.Task) error {
 	q := `
-		INSERT INTO tasks (user_id, category_id, status_id, priority_id, title, description, due_date)
-		VALUES ($1,$2,$3,$4,$5,$6,$7)
-		RETURNING id, created_at
+		INSERT INTO tasks (user_id, parent_id, category_id, status_id, priority_id, title, description, due_date, completed_at,
+		                   recurrence, series_id, occurrence, scheduled_for, due_all_day)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+		RETURNING id, created_at, revision
 	`
-	return r.db.QueryRowContext(ctx, q,
-		t.UserID, t.CategoryID, t.StatusID, t.PriorityID, t.Title, t.Description, t.DueDate,
-	).Scan(&t.ID, &t.CreatedAt)
+	if t.Occurrence == 0 {
+		t.Occurrence = 1
+	}
+	return conn(ctx, r.db).QueryRowContext(ctx, q,
+		t.UserID, t.ParentID, t.CategoryID, t.StatusID, t.PriorityID, t.Title, t.Description, t.DueDate, t.CompletedAt,
+		t.Recurrence, t.SeriesID, t.Occurrence, t.ScheduledFor, t.DueAllDay,
+	).Scan(&t.ID, &t.CreatedAt, &t.Revision)
 }
 
+// Update writes t only while the stored revision still equals t.Revision;
+// otherwise it returns sql.ErrNoRows. On success t.Revision is the new one.
 func (r *taskRepositoryPostgres) Update(ctx context.Context, t *model.Task) error {
 	q := `
 		UPDATE tasks
-		SET category_id=$1, status_id=$2, priority_id=$3, title=$4, description=$5, due_date=$6, updated_at=now()
-		WHERE id=$7 AND user_id=$8
-		RETURNING updated_at
+		SET category_id=$1, status_id=$2, priority_id=$3, title=$4, description=$5, due_date=$6,
+		    completed_at=$7, parent_id=$8, recurrence=$9, scheduled_for=$10, due_all_day=$11,
+		    updated_at=now(), revision=revision+1
+		WHERE id=$12 AND user_id=$13 AND revision=$14 AND deleted_at IS NULL
+		RETURNING updated_at, revision
 	`
 	var upd sql.NullTime
-	err := r.db.QueryRowContext(ctx, q,
-		t.CategoryID, t.StatusID, t.PriorityID, t.Title, t.Description, t.DueDate,
-		t.ID, t.UserID,
-	).Scan(&upd)
+	err := conn(ctx, r.db).QueryRowContext(ctx, q,
+		t.CategoryID, t.StatusID, t.PriorityID, t.Title, t.Description, t.DueDate, t.CompletedAt, t.ParentID,
+		t.Recurrence, t.ScheduledFor, t.DueAllDay,
+		t.ID, t.UserID, t.Revision,
+	).Scan(&upd, &t.Revision)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return sql.ErrNoRows
@@ -166,8 +458,337 @@ func (r *taskRepositoryPostgres) Update(ctx context.Context, t *model.Task) erro
 	return nil
 }
 
-func (r *taskRepositoryPostgres) Delete(ctx context.Context, taskID, userID string) error {
-	res, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE id=$1 AND user_id=$2`, taskID, userID)
+// taskColumnValue returns the value t holds for one of the columns that
+// Patch may write.
+func taskColumnValue(t *model.Task, column string) (any, bool) {
+	switch column {
+	case "parent_id":
+		return t.ParentID, true
+	case "category_id":
+		return t.CategoryID, true
+	case "status_id":
+		return t.StatusID, true
+	case "priority_id":
+		return t.PriorityID, true
+	case "title":
+		return t.Title, true
+	case "description":
+		return t.Description, true
+	case "due_date":
+		return t.DueDate, true
+	case "due_all_day":
+		return t.DueAllDay, true
+	case "completed_at":
+		return t.CompletedAt, true
+	case "recurrence":
+		return t.Recurrence, true
+	case "scheduled_for":
+		return t.ScheduledFor, true
+	case "archived_at":
+		return t.ArchivedAt, true
+	}
+	return nil, false
+}
+
+// ChangedTaskColumns lists the writable columns whose values differ between
+// before and after.
+func ChangedTaskColumns(before, after *model.Task) []string {
+	var columns []string
+	changed := func(column string, same bool) {
+		if !same {
+			columns = append(columns, column)
+		}
+	}
+	changed("parent_id", sameString(before.ParentID, after.ParentID))
+	changed("category_id", sameString(before.CategoryID, after.CategoryID))
+	changed("status_id", before.StatusID == after.StatusID)
+	changed("priority_id", before.PriorityID == after.PriorityID)
+	changed("title", before.Title == after.Title)
+	changed("description", sameString(before.Description, after.Description))
+	changed("due_date", sameTime(before.DueDate, after.DueDate))
+	changed("due_all_day", before.DueAllDay == after.DueAllDay)
+	changed("completed_at", sameTime(before.CompletedAt, after.CompletedAt))
+	changed("recurrence", sameString(before.Recurrence, after.Recurrence))
+	changed("scheduled_for", sameTime(before.ScheduledFor, after.ScheduledFor))
+	changed("archived_at", sameTime(before.ArchivedAt, after.ArchivedAt))
+	return columns
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (r *taskRepositoryPostgres) Patch(ctx context.Context, t *model.Task, columns []string) error {
+	sets := make([]string, 0, len(columns)+1)
+	args := make([]any, 0, len(columns)+2)
+	for _, column := range columns {
+		v, ok := taskColumnValue(t, column)
+		if !ok {
+			return fmt.Errorf("task column %q cannot be patched", column)
+		}
+		args = append(args, v)
+		sets = append(sets, fmt.Sprintf("%s=$%d", column, len(args)))
+	}
+	sets = append(sets, "updated_at=now()", "revision=revision+1")
+	args = append(args, t.ID, t.UserID, t.Revision)
+
+	q := fmt.Sprintf(`
+		UPDATE tasks
+		SET %s
+		WHERE id=$%d AND user_id=$%d AND revision=$%d AND deleted_at IS NULL
+		RETURNING updated_at, revision
+	`, strings.Join(sets, ", "), len(args)-2, len(args)-1, len(args))
+
+	var upd sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, args...).Scan(&upd, &t.Revision)
+	if err != nil {
+		return err
+	}
+	if upd.Valid {
+		v := upd.Time
+		t.UpdatedAt = &v
+	}
+	return nil
+}
+
+func (r *taskRepositoryPostgres) OccurrenceExists(ctx context.Context, seriesID string, occurrence int, userID string) (bool, error) {
+	var ok bool
+	q := `
+	SELECT EXISTS (
+		SELECT 1 FROM tasks WHERE COALESCE(series_id, id) = $1 AND occurrence = $2 AND user_id = $3
+	)`
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, seriesID, occurrence, userID).Scan(&ok)
+	return ok, err
+}
+
+// UpdateSeries copies the series-wide fields of t onto the open occurrences
+// that come after it.
+func (r *taskRepositoryPostgres) UpdateSeries(ctx context.Context, t *model.Task) ([]*model.Task, error) {
+	seriesID := t.ID
+	if t.SeriesID != nil {
+		seriesID = *t.SeriesID
+	}
+	var before []*model.Task
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		q := `
+			SELECT` + taskColumns + taskFrom + `
+			WHERE COALESCE(t.series_id, t.id) = $1 AND t.occurrence > $2 AND t.user_id = $3
+			  AND t.completed_at IS NULL AND t.deleted_at IS NULL
+			ORDER BY t.occurrence
+			FOR UPDATE OF t
+		`
+		rows, err := db.QueryContext(ctx, q, seriesID, t.Occurrence, t.UserID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return err
+			}
+			before = append(before, task)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			UPDATE tasks
+			SET title=$1, description=$2, priority_id=$3, category_id=$4, recurrence=$5, updated_at=now(), revision=revision+1
+			WHERE COALESCE(series_id, id) = $6 AND occurrence > $7 AND user_id = $8
+			  AND completed_at IS NULL AND deleted_at IS NULL
+		`, t.Title, t.Description, t.PriorityID, t.CategoryID, t.Recurrence, seriesID, t.Occurrence, t.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return before, nil
+}
+
+// Delete moves the task to the trash. Cascaded subtasks share its
+// deleted_at, which is how Restore finds them again.
+func (r *taskRepositoryPostgres) Delete(ctx context.Context, taskID, userID string, mode ChildDeleteMode) error {
+	return withTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		if mode == DeleteChildrenReparent {
+			_, err := db.ExecContext(ctx, `
+				UPDATE tasks
+				SET parent_id = (SELECT parent_id FROM tasks WHERE id=$1 AND user_id=$2), updated_at = now(), revision = revision + 1
+				WHERE parent_id=$1 AND user_id=$2 AND deleted_at IS NULL
+			`, taskID, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		res, err := db.ExecContext(ctx, descendantsCTE+`
+			UPDATE tasks SET deleted_at = now()
+			WHERE user_id = $2 AND deleted_at IS NULL
+			  AND (id = $1 OR id IN (SELECT id FROM tree))
+		`, taskID, userID)
+		if err != nil {
+			return err
+		}
+		aff, _ := res.RowsAffected()
+		if aff == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+func scanIDs(rows *sql.Rows, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *taskRepositoryPostgres) MoveCategory(ctx context.Context, userID, from string, to *string) ([]*model.Task, error) {
+	var before []*model.Task
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		q := `
+			SELECT` + taskColumns + taskFrom + `
+			WHERE t.category_id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL
+			ORDER BY t.id
+			FOR UPDATE OF t
+		`
+		rows, err := db.QueryContext(ctx, q, from, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return err
+			}
+			before = append(before, task)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			UPDATE tasks SET category_id = $3, updated_at = now(), revision = revision + 1
+			WHERE category_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		`, from, userID, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return before, nil
+}
+
+func (r *taskRepositoryPostgres) DeleteByCategory(ctx context.Context, categoryID, userID string) ([]string, error) {
+	return scanIDs(conn(ctx, r.db).QueryContext(ctx, `
+		WITH RECURSIVE tree AS (
+			SELECT id, ARRAY[id] AS path
+			FROM tasks
+			WHERE category_id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, tree.path || child.id
+			FROM tasks child
+			JOIN tree ON child.parent_id = tree.id
+			WHERE NOT child.id = ANY(tree.path) AND child.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = now()
+		WHERE id IN (SELECT id FROM tree)
+		RETURNING id
+	`, categoryID, userID))
+}
+
+func (r *taskRepositoryPostgres) ListTrashed(ctx context.Context, userID string) ([]*model.Task, error) {
+	// Subtasks trashed together with their parent are restored with it, so
+	// only the top of each trashed subtree is listed.
+	q := "SELECT" + taskColumns + taskFrom + `
+		WHERE t.user_id = $1 AND t.deleted_at IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM tasks parent
+			WHERE parent.id = t.parent_id AND parent.deleted_at = t.deleted_at
+		  )
+		ORDER BY t.deleted_at DESC, t.id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*model.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// Restore brings back a trashed task and the subtasks trashed with it. A
+// task whose parent is still in the trash comes back as a top-level task.
+func (r *taskRepositoryPostgres) Restore(ctx context.Context, taskID, userID string) error {
+	return withTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		var deletedAt time.Time
+		err := db.QueryRowContext(ctx,
+			`SELECT deleted_at FROM tasks WHERE id=$1 AND user_id=$2 AND deleted_at IS NOT NULL FOR UPDATE`,
+			taskID, userID).Scan(&deletedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, subtreeCTE+`
+			UPDATE tasks SET deleted_at = NULL, updated_at = now(), revision = revision + 1
+			WHERE user_id = $2 AND deleted_at = $3
+			  AND (id = $1 OR id IN (SELECT id FROM tree))
+		`, taskID, userID, deletedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			UPDATE tasks t SET parent_id = NULL
+			WHERE t.id = $1 AND t.user_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM tasks parent WHERE parent.id = t.parent_id AND parent.deleted_at IS NULL
+			)
+		`, taskID, userID)
+		return err
+	})
+}
+
+// Purge deletes a trashed task for good; its subtasks go with it through the
+// parent_id foreign key.
+func (r *taskRepositoryPostgres) Purge(ctx context.Context, taskID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM tasks WHERE id=$1 AND user_id=$2 AND deleted_at IS NOT NULL`, taskID, userID)
 	if err != nil {
 		return err
 	}
@@ -177,3 +798,92 @@ func (r *taskRepositoryPostgres) Delete(ctx context.Context, taskID, userID stri
 	}
 	return nil
 }
+
+func (r *taskRepositoryPostgres) PurgeTrashed(ctx context.Context, userID string, before time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM tasks
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		  AND ($2 = '' OR user_id::text = $2)
+	`, before, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// descendantsCTE walks the live subtree below $1 for user $2. The path
+// column stops the walk should a cycle ever slip into the data.
+const descendantsCTE = `
+		WITH RECURSIVE tree AS (
+			SELECT id, 1 AS depth, ARRAY[id] AS path
+			FROM tasks
+			WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, tree.depth + 1, tree.path || child.id
+			FROM tasks child
+			JOIN tree ON child.parent_id = tree.id
+			WHERE NOT child.id = ANY(tree.path) AND child.deleted_at IS NULL
+		)`
+
+// subtreeCTE is descendantsCTE including trashed tasks.
+const subtreeCTE = `
+		WITH RECURSIVE tree AS (
+			SELECT id, ARRAY[id] AS path
+			FROM tasks
+			WHERE parent_id = $1 AND user_id = $2
+			UNION ALL
+			SELECT child.id, tree.path || child.id
+			FROM tasks child
+			JOIN tree ON child.parent_id = tree.id
+			WHERE NOT child.id = ANY(tree.path)
+		)`
+
+func (r *taskRepositoryPostgres) ListDescendants(ctx context.Context, taskID, userID string, maxDepth int) ([]*model.TaskNode, error) {
+	q := descendantsCTE + `
+		SELECT` + taskColumns + `, tree.depth` + taskFrom + `
+		JOIN tree ON tree.id = t.id
+		WHERE $3::int = 0 OR tree.depth <= $3::int
+		ORDER BY tree.depth, t.created_at, t.id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID, userID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*model.TaskNode{}
+	for rows.Next() {
+		var depth int
+		t, err := scanTask(rows, &depth)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, &model.TaskNode{Task: *t, Depth: depth, Children: []*model.TaskNode{}})
+	}
+	return out, rows.Err()
+}
+
+func (r *taskRepositoryPostgres) IsDescendant(ctx context.Context, ancestorID, taskID, userID string) (bool, error) {
+	var ok bool
+	q := descendantsCTE + `
+		SELECT EXISTS (SELECT 1 FROM tree WHERE id = $3)
+	`
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, ancestorID, userID, taskID).Scan(&ok)
+	return ok, err
+}
+
+func (r *taskRepositoryPostgres) Progress(ctx context.Context, taskID, userID string) (*model.TaskProgress, error) {
+	var p model.TaskProgress
+	q := descendantsCTE + `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE t.completed_at IS NOT NULL)
+		FROM tree
+		JOIN tasks t ON t.id = tree.id
+	`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, taskID, userID).Scan(&p.Total, &p.Done); err != nil {
+		return nil, err
+	}
+	if p.Total > 0 {
+		p.Percent = math.Round(float64(p.Done)*10000/float64(p.Total)) / 100
+	}
+	return &p, nil
+}
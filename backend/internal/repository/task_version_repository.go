@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type TaskVersionRepository interface {
+	// Append stores v as the task's next version and sets v.Version.
+	Append(ctx context.Context, v *model.TaskVersion) error
+	Latest(ctx context.Context, taskID string) (int, error)
+	ListByTask(ctx context.Context, taskID, userID string) ([]*model.TaskVersion, error)
+	Get(ctx context.Context, taskID, userID string, version int) (*model.TaskVersion, error)
+}
+
+type taskVersionRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewTaskVersionRepository(db *sql.DB) TaskVersionRepository {
+	return &taskVersionRepositoryPostgres{db: db}
+}
+
+// Append relies on the caller holding the task's row lock (it runs in the
+// same transaction as the task update), so MAX(version)+1 cannot race.
+func (r *taskVersionRepositoryPostgres) Append(ctx context.Context, v *model.TaskVersion) error {
+	q := `
+		INSERT INTO task_versions (task_id, version, user_id, snapshot, reverted_from)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4
+		FROM task_versions
+		WHERE task_id = $1
+		RETURNING version, created_at
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, v.TaskID, v.UserID, []byte(v.Snapshot), v.RevertedFrom).
+		Scan(&v.Version, &v.CreatedAt)
+}
+
+func (r *taskVersionRepositoryPostgres) Latest(ctx context.Context, taskID string) (int, error) {
+	var n int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM task_versions WHERE task_id = $1`, taskID).Scan(&n)
+	return n, err
+}
+
+const taskVersionColumns = `
+	SELECT v.task_id, v.version, v.user_id, v.snapshot, v.reverted_from, v.created_at
+	FROM task_versions v
+	JOIN tasks t ON t.id = v.task_id
+	WHERE v.task_id = $1 AND t.user_id = $2`
+
+func scanTaskVersion(row rowScanner) (*model.TaskVersion, error) {
+	var v model.TaskVersion
+	var snapshot []byte
+	if err := row.Scan(&v.TaskID, &v.Version, &v.UserID, &snapshot, &v.RevertedFrom, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	v.Snapshot = snapshot
+	return &v, nil
+}
+
+func (r *taskVersionRepositoryPostgres) ListByTask(ctx context.Context, taskID, userID string) ([]*model.TaskVersion, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, taskVersionColumns+` ORDER BY v.version DESC`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*model.TaskVersion{}
+	for rows.Next() {
+		v, err := scanTaskVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func (r *taskVersionRepositoryPostgres) Get(ctx context.Context, taskID, userID string, version int) (*model.TaskVersion, error) {
+	v, err := scanTaskVersion(conn(ctx, r.db).QueryRowContext(ctx, taskVersionColumns+` AND v.version = $3`, taskID, userID, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return v, err
+}
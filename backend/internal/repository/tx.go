@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// dbtx is the part of *sql.DB and *sql.Tx the repositories use, so a query
+// can run either on the pool or inside the transaction carried by ctx.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// withTx runs fn inside a transaction. When ctx already carries one, fn joins
+// it and the outer caller decides whether to commit.
+func withTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// withSavepoint runs fn inside a savepoint of the transaction carried by ctx,
+// so a failing fn undoes only its own work and the transaction stays usable.
+// Without a transaction it behaves like withTx.
+func withSavepoint(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	if !ok {
+		return withTx(ctx, db, fn)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT sp"); err != nil {
+		return err
+	}
+	if err := fn(ctx); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT sp"); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT sp")
+	return err
+}
+
+// Transactor lets services group several repository calls into one
+// transaction; repositories pick it up from the context they are given.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinSavepoint runs fn so that its failure rolls back only fn's work.
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactorPostgres struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) Transactor {
+	return &transactorPostgres{db: db}
+}
+
+func (t *transactorPostgres) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, t.db, fn)
+}
+
+func (t *transactorPostgres) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withSavepoint(ctx, t.db, fn)
+}
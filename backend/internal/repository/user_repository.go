@@ -13,6 +13,8 @@ type UserRepository interface{
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindByID(ctx context.Context, id string) (*model.User, error)
 	GetAllUsers(ctx context.Context) ([]*model.User, error)
+	GetTimezone(ctx context.Context, id string) (string, error)
+	UpdateTimezone(ctx context.Context, id, timezone string) error
 }
 
 type userRepositoryPostgres struct {
@@ -31,7 +33,7 @@ func (r *userRepositoryPostgres) CreateUser(
 	INSERT INTO users (
 	name, email, password_hash) 
 	VALUES ($1, $2, $3)
-	RETURNING id, created_at
+	RETURNING id, timezone, created_at
 	`
 	err := r.db.QueryRowContext(
 		ctx, 
@@ -39,7 +41,7 @@ func (r *userRepositoryPostgres) CreateUser(
 		user.Name,
 		user.Email,
 		user.PasswordHash,
-	).Scan(&user.ID, &user.CreatedAt)
+	).Scan(&user.ID, &user.Timezone, &user.CreatedAt)
 
 	if err != nil {
 		return err
@@ -54,7 +56,7 @@ func (r *userRepositoryPostgres) FindByEmail(
 	) (*model.User, error) {
 
 		query := `
-			SELECT id, name, email, password_hash, created_at
+			SELECT id, name, email, password_hash, timezone, created_at
 			FROM users
 			WHERE email = $1
 		`
@@ -66,6 +68,7 @@ func (r *userRepositoryPostgres) FindByEmail(
 				&user.Name,
 				&user.Email,
 				&user.PasswordHash,
+				&user.Timezone,
 				&user.CreatedAt,
 			)
 		
@@ -85,7 +88,7 @@ func (r *userRepositoryPostgres) FindByID(
 	) (*model.User, error){
 
 		query := `
-			SELECT id, name, email, password_hash, created_at
+			SELECT id, name, email, password_hash, timezone, created_at
 			FROM users
 			WHERE id = $1
 		`
@@ -97,6 +100,7 @@ func (r *userRepositoryPostgres) FindByID(
 				&user.Name,
 				&user.Email,
 				&user.PasswordHash,
+				&user.Timezone,
 				&user.CreatedAt,
 			)
 		
@@ -114,7 +118,7 @@ func (r *userRepositoryPostgres) GetAllUsers(
 	) ([]*model.User, error) {
 
 		query := `
-			SELECT id, name, email, password_hash, created_at
+			SELECT id, name, email, password_hash, timezone, created_at
 			FROM users
 		`
 		rows, err := r.db.QueryContext(ctx, query)
@@ -131,6 +135,7 @@ func (r *userRepositoryPostgres) GetAllUsers(
 				&user.Name,
 				&user.Email,
 				&user.PasswordHash,
+				&user.Timezone,
 				&user.CreatedAt,
 			)
 			if err != nil {
@@ -140,4 +145,29 @@ func (r *userRepositoryPostgres) GetAllUsers(
 		}
 
 		return users, nil
-}
\ No newline at end of file
+}
+
+// GetTimezone returns the user's timezone, or "" for an unknown user.
+func (r *userRepositoryPostgres) GetTimezone(ctx context.Context, id string) (string, error) {
+	var tz string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT timezone FROM users WHERE id = $1`, id).Scan(&tz)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return tz, err
+}
+
+func (r *userRepositoryPostgres) UpdateTimezone(ctx context.Context, id, timezone string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET timezone = $2 WHERE id = $1`, id, timezone)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+type WorkflowRepository interface {
+	ListTransitions(ctx context.Context, userID string) ([]*model.StatusTransition, error)
+	CreateTransition(ctx context.Context, t *model.StatusTransition) error
+	DeleteTransition(ctx context.Context, transitionID int, userID string) error
+	RecordStatusChange(ctx context.Context, taskID, userID string, fromStatusID, toStatusID int, comment string) error
+}
+
+type workflowRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewWorkflowRepository(db *sql.DB) WorkflowRepository {
+	return &workflowRepositoryPostgres{db: db}
+}
+
+func (r *workflowRepositoryPostgres) ListTransitions(ctx context.Context, userID string) ([]*model.StatusTransition, error) {
+	q := `
+		SELECT id, user_id, from_status_id, to_status_id, requires_comment
+		FROM status_transitions
+		WHERE user_id = $1
+		ORDER BY from_status_id, to_status_id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*model.StatusTransition{}
+	for rows.Next() {
+		var t model.StatusTransition
+		if err := rows.Scan(&t.ID, &t.UserID, &t.FromStatusID, &t.ToStatusID, &t.RequiresComment); err != nil {
+			return nil, err
+		}
+		out = append(out, &t)
+	}
+	return out, rows.Err()
+}
+
+func (r *workflowRepositoryPostgres) CreateTransition(ctx context.Context, t *model.StatusTransition) error {
+	q := `
+		INSERT INTO status_transitions (user_id, from_status_id, to_status_id, requires_comment)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, from_status_id, to_status_id)
+		DO UPDATE SET requires_comment = EXCLUDED.requires_comment
+		RETURNING id
+	`
+	return conn(ctx, r.db).QueryRowContext(ctx, q, t.UserID, t.FromStatusID, t.ToStatusID, t.RequiresComment).Scan(&t.ID)
+}
+
+func (r *workflowRepositoryPostgres) DeleteTransition(ctx context.Context, transitionID int, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM status_transitions WHERE id=$1 AND user_id=$2`, transitionID, userID)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *workflowRepositoryPostgres) RecordStatusChange(ctx context.Context, taskID, userID string, fromStatusID, toStatusID int, comment string) error {
+	var c *string
+	if comment != "" {
+		c = &comment
+	}
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO task_status_changes (task_id, user_id, from_status_id, to_status_id, comment)
+		VALUES ($1, $2, $3, $4, $5)
+	`, taskID, userID, fromStatusID, toStatusID, c)
+	return err
+}
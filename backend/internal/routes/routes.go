@@ -8,22 +8,56 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/liaa-aa/task-manager-project/backend/internal/handler"
 	customMiddleware "github.com/liaa-aa/task-manager-project/backend/internal/middleware"
+	"github.com/liaa-aa/task-manager-project/backend/internal/notify"
 	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
 	"github.com/liaa-aa/task-manager-project/backend/internal/service"
+	"github.com/liaa-aa/task-manager-project/backend/internal/storage"
 )
 
-func SetupRoutes(db *sql.DB) *chi.Mux {
+func SetupRoutes(db *sql.DB, blobStore storage.BlobStore, notifier notify.Notifier) *chi.Mux {
 	userRepo := repository.NewUserRepositoryPostgres(db)
 	categoryRepo := repository.NewCategoryRepository(db)
 	statusPrioritiesRepo := repository.NewStatusPrioritiesRepositoryPostgres(db)
 	taskRepo := repository.NewTaskRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	taskEventRepo := repository.NewTaskEventRepository(db)
+	taskVersionRepo := repository.NewTaskVersionRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
 
 	authService := service.NewAuthService(userRepo)
-	taskService := service.NewTaskService(taskRepo, statusPrioritiesRepo, categoryRepo)
+	taskService := service.NewTaskService(
+		taskRepo, statusPrioritiesRepo, categoryRepo, workflowRepo, dependencyRepo, tagRepo,
+		taskEventRepo, taskVersionRepo, userRepo, reminderRepo, repository.NewTransactor(db),
+	)
 
 	authHandler := handler.NewAuthHandler(authService)
 	taskHandler := handler.NewTaskHandler(taskService)
-	categoryHandler := handler.NewCategoryHandler(service.NewCategoryService(categoryRepo))
+	categoryHandler := handler.NewCategoryHandler(service.NewCategoryService(
+		categoryRepo, taskRepo, taskEventRepo, taskVersionRepo, repository.NewTransactor(db),
+	))
+	statusPriorityHandler := handler.NewStatusPriorityHandler(service.NewStatusPriorityService(statusPrioritiesRepo))
+	dependencyHandler := handler.NewDependencyHandler(service.NewDependencyService(dependencyRepo, taskRepo, repository.NewTransactor(db)))
+	commentHandler := handler.NewCommentHandler(service.NewCommentService(commentRepo, taskRepo))
+	attachmentHandler := handler.NewAttachmentHandler(service.NewAttachmentService(attachmentRepo, taskRepo, blobStore))
+	trashHandler := handler.NewTrashHandler(service.NewTrashService(
+		taskRepo, categoryRepo, attachmentRepo, taskEventRepo, blobStore, repository.NewTransactor(db),
+	))
+	tagHandler := handler.NewTagHandler(service.NewTagService(tagRepo))
+	searchHandler := handler.NewSearchHandler(service.NewSearchService(repository.NewSearchRepository(db), userRepo))
+	viewHandler := handler.NewViewHandler(service.NewViewService(
+		repository.NewSavedViewRepository(db), taskRepo, statusPrioritiesRepo, userRepo,
+	))
+	reminderHandler := handler.NewReminderHandler(service.NewReminderService(
+		reminderRepo, taskRepo, userRepo, repository.NewTransactor(db), notifier,
+	))
+	notificationHandler := handler.NewNotificationHandler(service.NewNotificationService(
+		repository.NewNotificationRepository(db),
+	))
+	workflowHandler := handler.NewWorkflowHandler(service.NewWorkflowService(workflowRepo, statusPrioritiesRepo))
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
@@ -31,8 +65,9 @@ func SetupRoutes(db *sql.DB) *chi.Mux {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"http://localhost:5173", "https://task-manager-project-theta.vercel.app"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match"},
+		ExposedHeaders: []string{"ETag"},
 	}))
 
 	r.Group(func(r chi.Router) {
@@ -43,23 +78,111 @@ func SetupRoutes(db *sql.DB) *chi.Mux {
 	r.Group(func(r chi.Router) {
 		r.Use(customMiddleware.AuthMiddleware)
 		r.Get("/users", authHandler.GetAllUsers)
+		r.Get("/me", authHandler.Me)
+		r.Patch("/me", authHandler.UpdateSettings)
 	})
 
 	r.Route("/task", func(r chi.Router) {
 		r.Use(customMiddleware.AuthMiddleware)
 		r.Get("/", taskHandler.List)
 		r.Post("/", taskHandler.Create)
+		r.Get("/order", dependencyHandler.Order)
+		r.Post("/bulk", taskHandler.Bulk)
+		r.Post("/quick", taskHandler.Quick)
 		r.Get("/{id}", taskHandler.Get)
+		r.Get("/{id}/children", taskHandler.Children)
+		r.Get("/{id}/history", taskHandler.History)
+		r.Get("/{id}/versions", taskHandler.Versions)
+		r.Post("/{id}/revert", taskHandler.Revert)
+		r.Get("/{id}/dependencies", dependencyHandler.List)
+		r.Post("/{id}/dependencies", dependencyHandler.Add)
+		r.Delete("/{id}/dependencies/{blockerID}", dependencyHandler.Remove)
+		r.Get("/{id}/comments", commentHandler.List)
+		r.Post("/{id}/comments", commentHandler.Create)
+		r.Put("/{id}/comments/{commentID}", commentHandler.Update)
+		r.Delete("/{id}/comments/{commentID}", commentHandler.Delete)
+		r.Get("/{id}/attachments", attachmentHandler.List)
+		r.Post("/{id}/attachments", attachmentHandler.Upload)
+		r.Get("/{id}/attachments/{attachmentID}", attachmentHandler.Download)
+		r.Delete("/{id}/attachments/{attachmentID}", attachmentHandler.Delete)
+		r.Get("/{id}/reminders", reminderHandler.List)
+		r.Post("/{id}/reminders", reminderHandler.Create)
+		r.Delete("/{id}/reminders/{reminderID}", reminderHandler.Delete)
+		r.Post("/{id}/reminders/{reminderID}/snooze", reminderHandler.Snooze)
 		r.Put("/{id}", taskHandler.Update)
+		r.Patch("/{id}", taskHandler.Patch)
 		r.Delete("/{id}", taskHandler.Delete)
 	})
 
+	r.Route("/trash", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", trashHandler.List)
+		r.Delete("/", trashHandler.Empty)
+		r.Post("/{kind}/{id}/restore", trashHandler.Restore)
+		r.Delete("/{kind}/{id}", trashHandler.Purge)
+	})
+
 	r.Route("/category", func(r chi.Router) {
 		r.Use(customMiddleware.AuthMiddleware)
 		r.Get("/", categoryHandler.List)
 		r.Post("/", categoryHandler.Create)
+		r.Put("/order", categoryHandler.Reorder)
+		r.Put("/{id}", categoryHandler.Update)
+		r.Post("/{id}/move", categoryHandler.Move)
 		r.Delete("/{id}", categoryHandler.Delete)
 	})
 
+	r.Route("/search", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", searchHandler.Search)
+	})
+
+	r.Route("/view", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", viewHandler.List)
+		r.Post("/", viewHandler.Create)
+		r.Get("/{id}", viewHandler.Get)
+		r.Get("/{id}/tasks", viewHandler.Tasks)
+		r.Put("/{id}", viewHandler.Update)
+		r.Delete("/{id}", viewHandler.Delete)
+	})
+
+	r.Route("/notifications", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", notificationHandler.List)
+		r.Post("/{id}/read", notificationHandler.MarkRead)
+	})
+
+	r.Route("/tag", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", tagHandler.List)
+		r.Post("/", tagHandler.Create)
+		r.Put("/{id}", tagHandler.Rename)
+		r.Delete("/{id}", tagHandler.Delete)
+	})
+
+	r.Route("/status", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", statusPriorityHandler.ListStatuses)
+		r.Post("/", statusPriorityHandler.CreateStatus)
+		r.Put("/{id}", statusPriorityHandler.UpdateStatus)
+		r.Delete("/{id}", statusPriorityHandler.DeleteStatus)
+	})
+
+	r.Route("/priority", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", statusPriorityHandler.ListPriorities)
+		r.Post("/", statusPriorityHandler.CreatePriority)
+		r.Put("/{id}", statusPriorityHandler.UpdatePriority)
+		r.Delete("/{id}", statusPriorityHandler.DeletePriority)
+	})
+
+	r.Route("/workflow", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+		r.Get("/", workflowHandler.Get)
+		r.Post("/transitions", workflowHandler.AddTransition)
+		r.Delete("/transitions/{id}", workflowHandler.RemoveTransition)
+	})
+
 	return r
 }
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+	"github.com/liaa-aa/task-manager-project/backend/internal/storage"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("attachment type is not allowed")
+)
+
+const defaultMaxAttachmentSize = 10 << 20
+
+// allowedAttachmentTypes is matched against the sniffed content type, never
+// the one the client claims.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+type AttachmentService interface {
+	List(ctx context.Context, taskID, userID string) ([]*model.Attachment, error)
+	Upload(ctx context.Context, taskID, userID, filename string, r io.Reader) (*model.Attachment, error)
+	Open(ctx context.Context, taskID, attachmentID, userID string) (*model.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, taskID, attachmentID, userID string) error
+	MaxSize() int64
+}
+
+type attachmentService struct {
+	AttachmentRepository repository.AttachmentRepository
+	TaskRepository       repository.TaskRepository
+	BlobStore            storage.BlobStore
+	maxSize              int64
+}
+
+func NewAttachmentService(
+	attachmentRepository repository.AttachmentRepository,
+	taskRepository repository.TaskRepository,
+	blobStore storage.BlobStore,
+) AttachmentService {
+	maxSize := int64(defaultMaxAttachmentSize)
+	if v := os.Getenv("ATTACHMENT_MAX_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			maxSize = n
+		}
+	}
+	return &attachmentService{
+		AttachmentRepository: attachmentRepository,
+		TaskRepository:       taskRepository,
+		BlobStore:            blobStore,
+		maxSize:              maxSize,
+	}
+}
+
+func (s *attachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *attachmentService) ensureTask(ctx context.Context, taskID, userID string) error {
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return errors.New("task not found")
+	}
+	return nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, without control
+// characters, so it is safe to echo back in Content-Disposition.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+func newStorageKey(taskID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return taskID + "/" + hex.EncodeToString(b), nil
+}
+
+// countingReader fails once more than limit bytes have been read, so
+// oversize uploads stop streaming instead of filling the store.
+type countingReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.n > c.limit {
+		return n, ErrAttachmentTooLarge
+	}
+	return n, err
+}
+
+func (s *attachmentService) List(ctx context.Context, taskID, userID string) ([]*model.Attachment, error) {
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.AttachmentRepository.ListByTask(ctx, taskID)
+}
+
+func (s *attachmentService) Upload(ctx context.Context, taskID, userID, filename string, r io.Reader) (*model.Attachment, error) {
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, errors.New("attachment is empty")
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentType, contentType)
+	}
+
+	key, err := newStorageKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(br, hash), limit: s.maxSize}
+	if err := s.BlobStore.Put(ctx, key, counter); err != nil {
+		return nil, err
+	}
+
+	a := &model.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        counter.n,
+		Checksum:    "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+	}
+	if err := s.AttachmentRepository.Create(ctx, a); err != nil {
+		removeBlobs(ctx, s.BlobStore, []string{key})
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *attachmentService) get(ctx context.Context, taskID, attachmentID, userID string) (*model.Attachment, error) {
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	a, err := s.AttachmentRepository.GetByID(ctx, attachmentID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return a, nil
+}
+
+func (s *attachmentService) Open(ctx context.Context, taskID, attachmentID, userID string) (*model.Attachment, io.ReadCloser, error) {
+	a, err := s.get(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.BlobStore.Open(ctx, a.StorageKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, rc, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, taskID, attachmentID, userID string) error {
+	a, err := s.get(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.AttachmentRepository.Delete(ctx, a.ID, taskID); err != nil {
+		return err
+	}
+	removeBlobs(ctx, s.BlobStore, []string{a.StorageKey})
+	return nil
+}
+
+// removeBlobs deletes blobs whose metadata is already gone. Failures are only
+// logged: the rows no longer reference the blobs, so retrying cannot help the
+// caller.
+func removeBlobs(ctx context.Context, store storage.BlobStore, keys []string) {
+	for _, k := range keys {
+		if err := store.Delete(ctx, k); err != nil {
+			log.Printf("attachment blob %s not removed: %v", k, err)
+		}
+	}
+}
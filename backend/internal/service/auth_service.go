@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"os"
 	"strconv"
@@ -15,10 +16,14 @@ import (
 	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type AuthService interface {
 	Register(ctx context.Context, name, email, password string) (*model.User, error)
 	Login(ctx context.Context, email, password string) (*AuthResponse, error)
 	GetAllUsers(ctx context.Context) ([]*model.User, error)
+	Me(ctx context.Context, userID string) (*model.User, error)
+	UpdateTimezone(ctx context.Context, userID, timezone string) (*model.User, error)
 }
 
 type authService struct {
@@ -133,4 +138,31 @@ func (s *authService) Login(ctx context.Context, email, password string) (*AuthR
 
 func (s *authService) GetAllUsers(ctx context.Context) ([]*model.User, error) {
 	return s.UserRepository.GetAllUsers(ctx)
-}
\ No newline at end of file
+}
+
+func (s *authService) Me(ctx context.Context, userID string) (*model.User, error) {
+	user, err := s.UserRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateTimezone stores the zone that overdue and "today" are computed in.
+func (s *authService) UpdateTimezone(ctx context.Context, userID, timezone string) (*model.User, error) {
+	timezone = strings.TrimSpace(timezone)
+	if _, err := LoadTimezone(timezone); err != nil {
+		return nil, err
+	}
+	err := s.UserRepository.UpdateTimezone(ctx, userID, timezone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Me(ctx, userID)
+}
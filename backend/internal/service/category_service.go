@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/liaa-aa/task-manager-project/backend/internal/model"
@@ -10,58 +12,361 @@ import (
 )
 
 type CategoryService interface {
-	List(ctx context.Context, userID string) ([]*model.Category, error)
-	Create(ctx context.Context, userID, name string) (*model.Category, error)
-	Delete(ctx context.Context, categoryID, userID string) error
+	List(ctx context.Context, userID string, includeArchived bool) ([]*model.Category, error)
+	Create(ctx context.Context, category *model.Category) error
+	Update(ctx context.Context, category *model.Category, position *int) error
+	Reorder(ctx context.Context, userID string, ids []string) error
+	Move(ctx context.Context, categoryID, userID string, parentID *string) (*model.Category, error)
+	Delete(ctx context.Context, categoryID, userID, tasks string) (int, error)
 }
 
 type categoryService struct {
-	CategoryRepository repository.CategoryRepository
+	CategoryRepository    repository.CategoryRepository
+	TaskRepository        repository.TaskRepository
+	TaskEventRepository   repository.TaskEventRepository
+	TaskVersionRepository repository.TaskVersionRepository
+	Transactor            repository.Transactor
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository) CategoryService {
-	return &categoryService{CategoryRepository: categoryRepository}
+func NewCategoryService(
+	categoryRepository repository.CategoryRepository,
+	taskRepository repository.TaskRepository,
+	taskEventRepository repository.TaskEventRepository,
+	taskVersionRepository repository.TaskVersionRepository,
+	transactor repository.Transactor,
+) CategoryService {
+	return &categoryService{
+		CategoryRepository:    categoryRepository,
+		TaskRepository:        taskRepository,
+		TaskEventRepository:   taskEventRepository,
+		TaskVersionRepository: taskVersionRepository,
+		Transactor:            transactor,
+	}
+}
+
+var (
+	ErrInvalidCategoryTasks = errors.New("tasks must be uncategorize, reassign:{categoryID} or delete")
+	ErrCategoryHasTasks     = errors.New("category still has tasks")
+	ErrCategoryExists       = errors.New("category already exists")
+)
+
+// CategoryTasksError reports how many tasks block a category delete that
+// did not say what to do with them.
+type CategoryTasksError struct {
+	Message   string `json:"error"`
+	TaskCount int    `json:"task_count"`
 }
 
-func (s *categoryService) List(ctx context.Context, userID string) ([]*model.Category, error) {
-	return s.CategoryRepository.ListByUser(ctx, userID)
+func (e *CategoryTasksError) Error() string {
+	return e.Message
 }
 
-func (s *categoryService) Create(ctx context.Context, userID, name string) (*model.Category, error) {
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return nil, errors.New("category name cannot be empty")
+func (e *CategoryTasksError) Unwrap() error {
+	return ErrCategoryHasTasks
+}
+
+func (s *categoryService) List(ctx context.Context, userID string, includeArchived bool) ([]*model.Category, error) {
+	return s.CategoryRepository.ListByUser(ctx, userID, includeArchived)
+}
+
+var iconPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const maxIconLength = 40
+
+func (s *categoryService) validateCategory(ctx context.Context, category *model.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return errors.New("category name cannot be empty")
+	}
+	if strings.Contains(category.Name, "/") {
+		return errors.New("category name cannot contain /")
+	}
+	if category.Position < 0 {
+		return errors.New("position cannot be negative")
 	}
 
-	existing, err := s.CategoryRepository.GetByName(ctx, name, userID)
+	color, err := normalizeColor(category.Color)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if existing != nil {
-		return nil, errors.New("category already exists")
+	category.Color = color
+
+	if category.Icon != nil {
+		icon := strings.ToLower(strings.TrimSpace(*category.Icon))
+		switch {
+		case icon == "":
+			category.Icon = nil
+		case len(icon) > maxIconLength || !iconPattern.MatchString(icon):
+			return errors.New("icon must be a key like folder or shopping-cart")
+		default:
+			category.Icon = &icon
+		}
 	}
 
-	category := &model.Category{
-		Name:   name,
-		UserID: userID,
+	return s.checkNameFree(ctx, category.Name, category.UserID, category.ParentID, category.ID)
+}
+
+// checkNameFree rejects a name already used by a sibling under parentID.
+func (s *categoryService) checkNameFree(ctx context.Context, name, userID string, parentID *string, selfID string) error {
+	existing, err := s.CategoryRepository.GetByName(ctx, name, userID, parentID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+func (s *categoryService) Create(ctx context.Context, category *model.Category) error {
+	if category.ParentID != nil && *category.ParentID == "" {
+		category.ParentID = nil
+	}
+	if category.ParentID != nil {
+		parent, err := s.CategoryRepository.GetByID(ctx, *category.ParentID, category.UserID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return errors.New("parent category not found")
+		}
 	}
 
-	if err := s.CategoryRepository.Create(ctx, category); err != nil {
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	return s.CategoryRepository.Create(ctx, category)
+}
+
+// Update keeps the category's position when position is nil.
+func (s *categoryService) Update(ctx context.Context, category *model.Category, position *int) error {
+	existing, err := s.CategoryRepository.GetByID(ctx, category.ID, category.UserID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("category not found")
+	}
+
+	// Moving between parents goes through Move, which checks for cycles.
+	category.ParentID = existing.ParentID
+	category.Position = existing.Position
+	if position != nil {
+		category.Position = *position
+	}
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	if err := s.CategoryRepository.Update(ctx, category); err != nil {
+		return err
+	}
+	category.TaskCount = existing.TaskCount
+	return nil
+}
+
+// Reorder takes every non-trashed category of the user, archived ones
+// included, in the order they should be listed.
+func (s *categoryService) Reorder(ctx context.Context, userID string, ids []string) error {
+	categories, err := s.CategoryRepository.ListByUser(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		owned[c.ID] = true
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !owned[id] || seen[id] {
+			return errors.New("ids must list each category exactly once")
+		}
+		seen[id] = true
+	}
+	if len(ids) != len(categories) {
+		return errors.New("ids must list each category exactly once")
+	}
+
+	return s.CategoryRepository.Reorder(ctx, userID, ids)
+}
+
+// Move puts a category under parentID, or at the top level when parentID
+// is nil. A category cannot move below itself or one of its descendants.
+func (s *categoryService) Move(ctx context.Context, categoryID, userID string, parentID *string) (*model.Category, error) {
+	if parentID != nil && *parentID == "" {
+		parentID = nil
+	}
+
+	category, err := s.CategoryRepository.GetByID(ctx, categoryID, userID)
+	if err != nil {
 		return nil, err
 	}
+	if category == nil {
+		return nil, errors.New("category not found")
+	}
+
+	if parentID != nil {
+		if *parentID == categoryID {
+			return nil, errors.New("a category cannot be its own parent")
+		}
+		parent, err := s.CategoryRepository.GetByID(ctx, *parentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, errors.New("parent category not found")
+		}
+		cycle, err := s.CategoryRepository.IsDescendant(ctx, categoryID, *parentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, errors.New("a category cannot be moved below its own subcategory")
+		}
+	}
 
+	if err := s.checkNameFree(ctx, category.Name, userID, parentID, categoryID); err != nil {
+		return nil, err
+	}
+	if err := s.CategoryRepository.SetParent(ctx, categoryID, userID, parentID); err != nil {
+		return nil, err
+	}
+	category.ParentID = parentID
 	return category, nil
 }
 
-
-func (s *categoryService) Delete(ctx context.Context, categoryID, userID string) error {
-	owned, err := s.CategoryRepository.ExistOwned(ctx, categoryID, userID)
+// reparentChildren moves the subcategories of a category that is being
+// deleted up to its parent.
+func (s *categoryService) reparentChildren(ctx context.Context, categoryID, userID string) error {
+	category, err := s.CategoryRepository.GetByID(ctx, categoryID, userID)
 	if err != nil {
 		return err
 	}
-	if !owned {
-		return errors.New("category not found")
+	children, err := s.CategoryRepository.ListChildren(ctx, categoryID, userID)
+	if err != nil {
+		return err
 	}
+	for _, child := range children {
+		err := s.checkNameFree(ctx, child.Name, userID, category.ParentID, child.ID)
+		if errors.Is(err, ErrCategoryExists) {
+			return fmt.Errorf("subcategory %q clashes with a category of the same name one level up", child.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.CategoryRepository.SetParent(ctx, child.ID, userID, category.ParentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return s.CategoryRepository.Delete(ctx, categoryID)
-}
\ No newline at end of file
+// Delete moves the category to the trash. tasks says what happens to the
+// tasks filed under it: "uncategorize", "reassign:{categoryID}" or "delete"
+// (trash them with their subtasks). It may be empty only when the category
+// has no tasks. Subcategories move up to the deleted category's parent. It
+// returns the number of tasks affected.
+func (s *categoryService) Delete(ctx context.Context, categoryID, userID, tasks string) (int, error) {
+	action, target, _ := strings.Cut(tasks, ":")
+	switch action {
+	case "", "uncategorize", "delete":
+		if target != "" {
+			return 0, ErrInvalidCategoryTasks
+		}
+	case "reassign":
+		if target == "" {
+			return 0, ErrInvalidCategoryTasks
+		}
+	default:
+		return 0, ErrInvalidCategoryTasks
+	}
+
+	var affected int
+	err := s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		owned, err := s.CategoryRepository.ExistOwned(ctx, categoryID, userID)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			return errors.New("category not found")
+		}
+
+		var ids []string
+		var events []*model.TaskEvent
+		switch action {
+		case "":
+			count, err := s.CategoryRepository.CountTasks(ctx, categoryID)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return &CategoryTasksError{
+					Message:   fmt.Sprintf("category still has %d tasks; pass tasks=uncategorize, reassign:{categoryID} or delete", count),
+					TaskCount: count,
+				}
+			}
+
+		case "uncategorize", "reassign":
+			var to *string
+			if action == "reassign" {
+				if target == categoryID {
+					return errors.New("cannot reassign tasks to the category being deleted")
+				}
+				ok, err := s.CategoryRepository.ExistOwned(ctx, target, userID)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("target category not found")
+				}
+				to = &target
+			}
+			before, err := s.TaskRepository.MoveCategory(ctx, userID, categoryID, to)
+			if err != nil {
+				return err
+			}
+			for _, b := range before {
+				after := *b
+				after.CategoryID = to
+				after.Revision++
+				events = append(events, &model.TaskEvent{
+					TaskID:  b.ID,
+					Action:  model.TaskEventUpdated,
+					Changes: diffTask(b, &after),
+				})
+				if err := saveVersion(ctx, s.TaskVersionRepository, userID, b, &after, nil); err != nil {
+					return err
+				}
+				ids = append(ids, b.ID)
+			}
+
+		case "delete":
+			if ids, err = s.TaskRepository.DeleteByCategory(ctx, categoryID, userID); err != nil {
+				return err
+			}
+			for _, id := range ids {
+				events = append(events, &model.TaskEvent{
+					TaskID:  id,
+					Action:  model.TaskEventDeleted,
+					Changes: map[string]model.FieldChange{},
+				})
+			}
+		}
+
+		for _, e := range events {
+			e.UserID = userID
+			if err := s.TaskEventRepository.Record(ctx, e); err != nil {
+				return err
+			}
+		}
+		affected = len(ids)
+		if err := s.reparentChildren(ctx, categoryID, userID); err != nil {
+			return err
+		}
+		return s.CategoryRepository.Delete(ctx, categoryID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("only the author can change a comment")
+)
+
+const maxCommentLength = 10000
+
+type CommentService interface {
+	List(ctx context.Context, taskID, userID string) ([]*model.Comment, error)
+	Create(ctx context.Context, taskID, userID, body string) (*model.Comment, error)
+	Update(ctx context.Context, taskID, commentID, userID, body string) (*model.Comment, error)
+	Delete(ctx context.Context, taskID, commentID, userID string) error
+}
+
+type commentService struct {
+	CommentRepository repository.CommentRepository
+	TaskRepository    repository.TaskRepository
+}
+
+func NewCommentService(
+	commentRepository repository.CommentRepository,
+	taskRepository repository.TaskRepository,
+) CommentService {
+	return &commentService{
+		CommentRepository: commentRepository,
+		TaskRepository:    taskRepository,
+	}
+}
+
+// validateCommentBody checks a Markdown body; it is stored as given.
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("comment body cannot be empty")
+	}
+	if len(body) > maxCommentLength {
+		return errors.New("comment body is too long")
+	}
+	return nil
+}
+
+// ensureTask checks that the task exists and is visible to userID.
+func (s *commentService) ensureTask(ctx context.Context, taskID, userID string) error {
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// ownComment loads a comment on the task and checks that userID wrote it.
+func (s *commentService) ownComment(ctx context.Context, taskID, commentID, userID string) (*model.Comment, error) {
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	c, err := s.CommentRepository.GetByID(ctx, commentID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCommentNotFound
+	}
+	if c.UserID != userID {
+		return nil, ErrCommentForbidden
+	}
+	return c, nil
+}
+
+func (s *commentService) List(ctx context.Context, taskID, userID string) ([]*model.Comment, error) {
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.CommentRepository.ListByTask(ctx, taskID)
+}
+
+func (s *commentService) Create(ctx context.Context, taskID, userID, body string) (*model.Comment, error) {
+	if err := validateCommentBody(body); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	c := &model.Comment{TaskID: taskID, UserID: userID, Body: body}
+	if err := s.CommentRepository.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *commentService) Update(ctx context.Context, taskID, commentID, userID, body string) (*model.Comment, error) {
+	if err := validateCommentBody(body); err != nil {
+		return nil, err
+	}
+	c, err := s.ownComment(ctx, taskID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Body = body
+	if err := s.CommentRepository.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *commentService) Delete(ctx context.Context, taskID, commentID, userID string) error {
+	if _, err := s.ownComment(ctx, taskID, commentID, userID); err != nil {
+		return err
+	}
+	return s.CommentRepository.Delete(ctx, commentID, userID)
+}
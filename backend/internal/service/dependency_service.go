@@ -0,0 +1,208 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type TaskDependencies struct {
+	TaskID    string   `json:"task_id"`
+	BlockedBy []string `json:"blocked_by"`
+	Blocking  []string `json:"blocking"`
+}
+
+type DependencyService interface {
+	Add(ctx context.Context, userID, blockerID, blockedID string) (*model.TaskDependency, error)
+	Remove(ctx context.Context, userID, blockerID, blockedID string) error
+	List(ctx context.Context, taskID, userID string) (*TaskDependencies, error)
+	Order(ctx context.Context, userID string) ([]*model.Task, error)
+}
+
+type dependencyService struct {
+	DependencyRepository repository.DependencyRepository
+	TaskRepository       repository.TaskRepository
+	Transactor           repository.Transactor
+}
+
+func NewDependencyService(
+	dependencyRepository repository.DependencyRepository,
+	taskRepository repository.TaskRepository,
+	transactor repository.Transactor,
+) DependencyService {
+	return &dependencyService{
+		DependencyRepository: dependencyRepository,
+		TaskRepository:       taskRepository,
+		Transactor:           transactor,
+	}
+}
+
+// reachable reports whether to can be reached from from by following edges.
+func reachable(edges map[string][]string, from, to string) bool {
+	seen := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == to {
+			return true
+		}
+		for _, next := range edges[n] {
+			if !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
+
+func (s *dependencyService) Add(ctx context.Context, userID, blockerID, blockedID string) (*model.TaskDependency, error) {
+	if blockerID == "" || blockedID == "" {
+		return nil, errors.New("blocker and blocked task IDs are required")
+	}
+	if blockerID == blockedID {
+		return nil, errors.New("a task cannot block itself")
+	}
+
+	for _, id := range []string{blockerID, blockedID} {
+		t, err := s.TaskRepository.GetByID(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, errors.New("task not found")
+		}
+	}
+
+	// The cycle check and the insert run under the user's graph lock, so two
+	// concurrent adds of A->B and B->A cannot both pass the check.
+	d := &model.TaskDependency{BlockerID: blockerID, BlockedID: blockedID}
+	err := s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.DependencyRepository.LockGraph(ctx, userID); err != nil {
+			return err
+		}
+		// Edges to trashed tasks count too: they come back on restore.
+		deps, err := s.DependencyRepository.ListAllByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		edges := map[string][]string{}
+		for _, d := range deps {
+			edges[d.BlockerID] = append(edges[d.BlockerID], d.BlockedID)
+		}
+		// The new edge blocker -> blocked closes a cycle if blocked already
+		// (transitively) blocks blocker.
+		if reachable(edges, blockedID, blockerID) {
+			return errors.New("dependency would create a cycle")
+		}
+		return s.DependencyRepository.Add(ctx, userID, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *dependencyService) Remove(ctx context.Context, userID, blockerID, blockedID string) error {
+	err := s.DependencyRepository.Remove(ctx, userID, blockerID, blockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("dependency not found")
+	}
+	return err
+}
+
+func (s *dependencyService) List(ctx context.Context, taskID, userID string) (*TaskDependencies, error) {
+	deps, err := s.DependencyRepository.ListForTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TaskDependencies{TaskID: taskID, BlockedBy: []string{}, Blocking: []string{}}
+	for _, d := range deps {
+		if d.BlockedID == taskID {
+			out.BlockedBy = append(out.BlockedBy, d.BlockerID)
+		} else {
+			out.Blocking = append(out.Blocking, d.BlockedID)
+		}
+	}
+	return out, nil
+}
+
+// readyQueue pops the ready task that came first in the base ordering.
+type readyQueue []int
+
+func (q readyQueue) Len() int           { return len(q) }
+func (q readyQueue) Less(i, j int) bool { return q[i] < q[j] }
+func (q readyQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *readyQueue) Push(x any)        { *q = append(*q, x.(int)) }
+func (q *readyQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// Order returns the user's open tasks so that every task comes after the
+// tasks blocking it. Among tasks that are ready at the same time the earlier
+// due date wins.
+func (s *dependencyService) Order(ctx context.Context, userID string) ([]*model.Task, error) {
+	page, err := s.TaskRepository.ListByUser(ctx, userID, repository.TaskFilter{Sort: "due_date", Order: "asc"})
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.DependencyRepository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*model.Task
+	index := map[string]int{}
+	for _, t := range page.Tasks {
+		if t.CompletedAt != nil {
+			continue
+		}
+		index[t.ID] = len(tasks)
+		tasks = append(tasks, t)
+	}
+
+	edges := make([][]int, len(tasks))
+	inDegree := make([]int, len(tasks))
+	for _, d := range deps {
+		from, ok1 := index[d.BlockerID]
+		to, ok2 := index[d.BlockedID]
+		if !ok1 || !ok2 {
+			continue
+		}
+		edges[from] = append(edges[from], to)
+		inDegree[to]++
+	}
+
+	ready := &readyQueue{}
+	for i := range tasks {
+		if inDegree[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	out := make([]*model.Task, 0, len(tasks))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		out = append(out, tasks[i])
+		for _, j := range edges[i] {
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+
+	if len(out) != len(tasks) {
+		return nil, errors.New("task dependencies contain a cycle")
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+const notificationListLimit = 100
+
+type NotificationService interface {
+	List(ctx context.Context, userID string, unreadOnly bool) ([]*model.Notification, error)
+	MarkRead(ctx context.Context, notificationID, userID string) error
+}
+
+type notificationService struct {
+	NotificationRepository repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepository repository.NotificationRepository) NotificationService {
+	return &notificationService{NotificationRepository: notificationRepository}
+}
+
+// List returns the user's newest notifications first.
+func (s *notificationService) List(ctx context.Context, userID string, unreadOnly bool) ([]*model.Notification, error) {
+	return s.NotificationRepository.ListByUser(ctx, userID, unreadOnly, notificationListLimit)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, notificationID, userID string) error {
+	err := s.NotificationRepository.MarkRead(ctx, notificationID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotificationNotFound
+	}
+	return err
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/notify"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+var (
+	ErrReminderNotFound = errors.New("reminder not found")
+	ErrInvalidReminder  = errors.New("invalid reminder")
+)
+
+const (
+	// allDayReminderHour is the local hour offsets of an all-day task count
+	// back from, so "1 day before" lands on the previous morning.
+	allDayReminderHour = 9
+	maxReminderOffset  = 365 * 24 * 60
+	maxSnooze          = 365 * 24 * time.Hour
+	// maxReminderAttempts is how often delivery is tried before giving up.
+	maxReminderAttempts = 5
+	// reminderLease is how long a claimed reminder is left alone before it
+	// counts as due again. It outlasts a full batch of slow webhooks.
+	reminderLease = 30 * time.Minute
+)
+
+// ReminderInput sets exactly one of an absolute time and an offset in
+// minutes before the task is due.
+type ReminderInput struct {
+	RemindAt      *time.Time
+	OffsetMinutes *int
+}
+
+type ReminderService interface {
+	List(ctx context.Context, taskID, userID string) ([]*model.Reminder, error)
+	Create(ctx context.Context, taskID, userID string, in ReminderInput) (*model.Reminder, error)
+	Delete(ctx context.Context, taskID, reminderID, userID string) error
+	Snooze(ctx context.Context, taskID, reminderID, userID string, until time.Time) (*model.Reminder, error)
+	// FireDue delivers up to limit due reminders and reports how many it
+	// claimed.
+	FireDue(ctx context.Context, limit int) (int, error)
+}
+
+type reminderService struct {
+	ReminderRepository repository.ReminderRepository
+	TaskRepository     repository.TaskRepository
+	UserRepository     repository.UserRepository
+	Transactor         repository.Transactor
+	Notifier           notify.Notifier
+}
+
+func NewReminderService(
+	reminderRepository repository.ReminderRepository,
+	taskRepository repository.TaskRepository,
+	userRepository repository.UserRepository,
+	transactor repository.Transactor,
+	notifier notify.Notifier,
+) ReminderService {
+	return &reminderService{
+		ReminderRepository: reminderRepository,
+		TaskRepository:     taskRepository,
+		UserRepository:     userRepository,
+		Transactor:         transactor,
+		Notifier:           notifier,
+	}
+}
+
+func (s *reminderService) getTask(ctx context.Context, taskID, userID string) (*model.Task, error) {
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (s *reminderService) List(ctx context.Context, taskID, userID string) ([]*model.Reminder, error) {
+	if _, err := s.getTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.ReminderRepository.ListByTask(ctx, taskID)
+}
+
+func (s *reminderService) Create(ctx context.Context, taskID, userID string, in ReminderInput) (*model.Reminder, error) {
+	if (in.RemindAt == nil) == (in.OffsetMinutes == nil) {
+		return nil, fmt.Errorf("%w: set exactly one of remind_at and offset_minutes", ErrInvalidReminder)
+	}
+	task, err := s.getTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &model.Reminder{TaskID: taskID, UserID: userID}
+	if in.RemindAt != nil {
+		if !in.RemindAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: remind_at is in the past", ErrInvalidReminder)
+		}
+		at := in.RemindAt.UTC()
+		reminder.RemindAt, reminder.FireAt = &at, &at
+	} else {
+		offset := *in.OffsetMinutes
+		if offset < 0 || offset > maxReminderOffset {
+			return nil, fmt.Errorf("%w: offset_minutes must be between 0 and %d", ErrInvalidReminder, maxReminderOffset)
+		}
+		reminder.OffsetMinutes = &offset
+		base, err := reminderBase(ctx, s.UserRepository, task)
+		if err != nil {
+			return nil, err
+		}
+		if base != nil {
+			at := base.Add(-time.Duration(offset) * time.Minute)
+			reminder.FireAt = &at
+		}
+	}
+
+	if err := s.ReminderRepository.Create(ctx, reminder); err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+func (s *reminderService) Delete(ctx context.Context, taskID, reminderID, userID string) error {
+	if _, err := s.getTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+	err := s.ReminderRepository.Delete(ctx, reminderID, taskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrReminderNotFound
+	}
+	return err
+}
+
+// Snooze moves the reminder's next firing to until, re-arming it if it has
+// already gone off. An offset reminder snaps back to its offset when the
+// task's due date changes.
+func (s *reminderService) Snooze(ctx context.Context, taskID, reminderID, userID string, until time.Time) (*model.Reminder, error) {
+	now := time.Now()
+	if !until.After(now) || until.Sub(now) > maxSnooze {
+		return nil, fmt.Errorf("%w: snooze must end in the future and within a year", ErrInvalidReminder)
+	}
+	if _, err := s.getTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	err := s.ReminderRepository.Snooze(ctx, reminderID, taskID, until.UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReminderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.ReminderRepository.GetByID(ctx, reminderID, taskID)
+}
+
+// FireDue leases due reminders, delivers them with no transaction open, and
+// then records every outcome in one short transaction. Failed deliveries back
+// off exponentially until maxReminderAttempts is reached.
+func (s *reminderService) FireDue(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	lease := now.Add(reminderLease).Truncate(time.Microsecond)
+	due, err := s.ReminderRepository.ClaimDue(ctx, now, lease, limit)
+	if err != nil || len(due) == 0 {
+		return 0, err
+	}
+
+	failures := make([]error, len(due))
+	for i, d := range due {
+		if !d.Closed {
+			failures[i] = s.Notifier.Notify(ctx, reminderMessage(d))
+		}
+	}
+
+	err = s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for i, d := range due {
+			if failures[i] == nil {
+				if err := s.ReminderRepository.MarkFired(ctx, d.ID, lease); err != nil {
+					return err
+				}
+				continue
+			}
+			log.Printf("reminder %s: delivery failed: %v", d.ID, failures[i])
+			if err := s.ReminderRepository.MarkFailed(ctx, d.ID, lease, failures[i].Error(), reminderRetryAt(d.Attempts)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(due), err
+}
+
+// reminderRetryAt is when to try again after a delivery failed with attempts
+// earlier failures behind it, or nil once the reminder should be given up.
+func reminderRetryAt(attempts int) *time.Time {
+	if attempts+1 >= maxReminderAttempts {
+		return nil
+	}
+	at := time.Now().Add(time.Duration(1<<attempts) * time.Minute)
+	return &at
+}
+
+func reminderMessage(d *repository.DueReminder) notify.Message {
+	m := notify.Message{
+		UserID:     d.UserID,
+		TaskID:     d.TaskID,
+		ReminderID: d.ID,
+		Title:      d.TaskTitle,
+		DueDate:    d.DueDate,
+		DueAllDay:  d.DueAllDay,
+	}
+	switch {
+	case d.DueDate == nil:
+		m.Body = "Reminder"
+	case d.DueAllDay:
+		m.Body = "Due " + d.DueDate.UTC().Format("Mon, Jan 2")
+	default:
+		loc, err := LoadTimezone(d.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		m.Body = "Due " + d.DueDate.In(loc).Format("Mon, Jan 2 15:04 MST")
+	}
+	return m
+}
+
+// reminderBase is the moment offset reminders count back from: the due time
+// itself, or allDayReminderHour on the due day in the user's timezone. It is
+// nil when the task has no due date.
+func reminderBase(ctx context.Context, users repository.UserRepository, task *model.Task) (*time.Time, error) {
+	if task.DueDate == nil {
+		return nil, nil
+	}
+	if !task.DueAllDay {
+		base := *task.DueDate
+		return &base, nil
+	}
+	loc, err := userLocation(ctx, users, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	y, m, d := task.DueDate.UTC().Date()
+	base := time.Date(y, m, d, allDayReminderHour, 0, 0, 0, loc)
+	return &base, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/notify"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type fakeReminderRepo struct {
+	repository.ReminderRepository
+	due    []*repository.DueReminder
+	lease  time.Time
+	fired  []string
+	failed map[string]*time.Time
+	inTx   bool
+	// txDuring records whether each Mark call ran inside a transaction.
+	txDuring []bool
+}
+
+func (r *fakeReminderRepo) ClaimDue(ctx context.Context, now, lease time.Time, limit int) ([]*repository.DueReminder, error) {
+	r.lease = lease
+	return r.due, nil
+}
+
+func (r *fakeReminderRepo) MarkFired(ctx context.Context, id string, lease time.Time) error {
+	r.txDuring = append(r.txDuring, r.inTx)
+	r.fired = append(r.fired, id)
+	return nil
+}
+
+func (r *fakeReminderRepo) MarkFailed(ctx context.Context, id string, lease time.Time, msg string, retryAt *time.Time) error {
+	r.txDuring = append(r.txDuring, r.inTx)
+	r.failed[id] = retryAt
+	return nil
+}
+
+type fakeTransactor struct{ repo *fakeReminderRepo }
+
+func (t fakeTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	t.repo.inTx = true
+	defer func() { t.repo.inTx = false }()
+	return fn(ctx)
+}
+
+func (t fakeTransactor) WithinSavepoint(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeNotifier struct {
+	repo *fakeReminderRepo
+	fail map[string]bool
+	sent []string
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, m notify.Message) error {
+	if n.repo.inTx {
+		return errors.New("delivered inside a transaction")
+	}
+	if n.fail[m.ReminderID] {
+		return errors.New("endpoint down")
+	}
+	n.sent = append(n.sent, m.ReminderID)
+	return nil
+}
+
+func dueReminder(id string, attempts int, closed bool) *repository.DueReminder {
+	return &repository.DueReminder{
+		Reminder:  model.Reminder{ID: id, TaskID: "task-" + id, UserID: "user", Attempts: attempts},
+		TaskTitle: "Task " + id,
+		Closed:    closed,
+	}
+}
+
+func TestFireDue(t *testing.T) {
+	repo := &fakeReminderRepo{
+		due: []*repository.DueReminder{
+			dueReminder("ok", 0, false),
+			dueReminder("closed", 0, true),
+			dueReminder("retry", 2, false),
+			dueReminder("give-up", maxReminderAttempts-1, false),
+		},
+		failed: map[string]*time.Time{},
+	}
+	notifier := &fakeNotifier{repo: repo, fail: map[string]bool{"retry": true, "give-up": true}}
+	s := &reminderService{ReminderRepository: repo, Transactor: fakeTransactor{repo}, Notifier: notifier}
+
+	start := time.Now()
+	n, err := s.FireDue(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("claimed = %d, want 4", n)
+	}
+	if repo.lease.Before(start.Add(reminderLease).Truncate(time.Microsecond)) {
+		t.Errorf("lease %v is shorter than reminderLease", repo.lease)
+	}
+
+	if len(notifier.sent) != 1 || notifier.sent[0] != "ok" {
+		t.Errorf("sent = %v, want [ok]", notifier.sent)
+	}
+	if len(repo.fired) != 2 || repo.fired[0] != "ok" || repo.fired[1] != "closed" {
+		t.Errorf("fired = %v, want [ok closed]", repo.fired)
+	}
+	for i, inTx := range repo.txDuring {
+		if !inTx {
+			t.Errorf("outcome %d recorded outside a transaction", i)
+		}
+	}
+
+	retryAt, ok := repo.failed["retry"]
+	if !ok || retryAt == nil {
+		t.Fatalf("retry: got %v, want a retry time", retryAt)
+	}
+	// Two earlier attempts back off 1<<2 = 4 minutes.
+	if d := retryAt.Sub(start); d < 4*time.Minute || d > 4*time.Minute+time.Minute {
+		t.Errorf("retry backoff = %v, want about 4m", d)
+	}
+	if at, ok := repo.failed["give-up"]; !ok || at != nil {
+		t.Errorf("give-up: got %v, want it retired", at)
+	}
+}
+
+func TestReminderRetryAt(t *testing.T) {
+	for attempts := 0; attempts < maxReminderAttempts; attempts++ {
+		at := reminderRetryAt(attempts)
+		if attempts == maxReminderAttempts-1 {
+			if at != nil {
+				t.Errorf("attempts %d: retry at %v, want give up", attempts, at)
+			}
+			continue
+		}
+		want := time.Duration(1<<attempts) * time.Minute
+		if d := time.Until(*at); d > want || d < want-time.Second {
+			t.Errorf("attempts %d: backoff %v, want %v", attempts, d, want)
+		}
+	}
+}
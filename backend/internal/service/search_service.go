@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type SearchService interface {
+	Search(ctx context.Context, userID, query string, kinds []string, filter repository.TaskFilter) ([]*model.SearchResult, error)
+}
+
+type searchService struct {
+	SearchRepository repository.SearchRepository
+	UserRepository   repository.UserRepository
+}
+
+func NewSearchService(searchRepository repository.SearchRepository, userRepository repository.UserRepository) SearchService {
+	return &searchService{SearchRepository: searchRepository, UserRepository: userRepository}
+}
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchWords     = 16
+)
+
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// prefixQuery turns free text into a to_tsquery expression that matches
+// every word as a prefix, so "proj rev" finds "project review". Only
+// letters and digits survive, which keeps tsquery operators out.
+func prefixQuery(text string) string {
+	words := searchWordPattern.FindAllString(strings.ToLower(text), maxSearchWords)
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+func (s *searchService) Search(ctx context.Context, userID, query string, kinds []string, filter repository.TaskFilter) ([]*model.SearchResult, error) {
+	tsq := prefixQuery(query)
+	if tsq == "" {
+		return nil, fmt.Errorf("%w: q must contain at least one word", ErrInvalidSearch)
+	}
+
+	if len(kinds) == 0 {
+		kinds = []string{model.SearchKindTask, model.SearchKindComment, model.SearchKindCategory}
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case model.SearchKindTask, model.SearchKindComment, model.SearchKindCategory:
+		default:
+			return nil, fmt.Errorf("%w: types must be task, comment or category", ErrInvalidSearch)
+		}
+	}
+
+	// Results are ordered by rank, so sort and cursor do not apply.
+	if filter.Cursor != nil {
+		return nil, fmt.Errorf("%w: cursor is not supported", ErrInvalidSearch)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	loc, err := userLocation(ctx, s.UserRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.Location = loc
+	if err := validateTaskFilter(&filter); err != nil {
+		return nil, err
+	}
+	if filter.Limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, maxSearchLimit)
+	}
+
+	return s.SearchRepository.Search(ctx, repository.SearchQuery{
+		UserID:  userID,
+		TSQuery: tsq,
+		Kinds:   kinds,
+		Filter:  filter,
+		Limit:   filter.Limit,
+	})
+}
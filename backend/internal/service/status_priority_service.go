@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type StatusPriorityService interface {
+	ListStatuses(ctx context.Context, userID string) ([]*model.Status, error)
+	CreateStatus(ctx context.Context, status *model.Status) error
+	UpdateStatus(ctx context.Context, status *model.Status) error
+	DeleteStatus(ctx context.Context, statusID int, userID string) error
+
+	ListPriorities(ctx context.Context, userID string) ([]*model.Priority, error)
+	CreatePriority(ctx context.Context, priority *model.Priority) error
+	UpdatePriority(ctx context.Context, priority *model.Priority) error
+	DeletePriority(ctx context.Context, priorityID int, userID string) error
+}
+
+type statusPriorityService struct {
+	StatusPrioritiesRepository repository.StatusPrioritiesRepository
+}
+
+func NewStatusPriorityService(statusPrioritiesRepository repository.StatusPrioritiesRepository) StatusPriorityService {
+	return &statusPriorityService{StatusPrioritiesRepository: statusPrioritiesRepository}
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func normalizeColor(color *string) (*string, error) {
+	if color == nil || strings.TrimSpace(*color) == "" {
+		return nil, nil
+	}
+	c := strings.ToLower(strings.TrimSpace(*color))
+	if !colorPattern.MatchString(c) {
+		return nil, errors.New("color must be a hex value like #1e90ff")
+	}
+	return &c, nil
+}
+
+func (s *statusPriorityService) ListStatuses(ctx context.Context, userID string) ([]*model.Status, error) {
+	return s.StatusPrioritiesRepository.ListStatuses(ctx, userID)
+}
+
+func (s *statusPriorityService) validateStatus(ctx context.Context, status *model.Status) error {
+	status.Name = strings.TrimSpace(status.Name)
+	if status.UserID == nil || *status.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if status.Name == "" {
+		return errors.New("status name cannot be empty")
+	}
+	if status.Position < 0 {
+		return errors.New("position cannot be negative")
+	}
+
+	color, err := normalizeColor(status.Color)
+	if err != nil {
+		return err
+	}
+	status.Color = color
+
+	taken, err := s.StatusPrioritiesRepository.StatusNameTaken(ctx, status.Name, *status.UserID, status.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("status already exists")
+	}
+	return nil
+}
+
+func (s *statusPriorityService) CreateStatus(ctx context.Context, status *model.Status) error {
+	if err := s.validateStatus(ctx, status); err != nil {
+		return err
+	}
+	return s.StatusPrioritiesRepository.CreateStatus(ctx, status)
+}
+
+func (s *statusPriorityService) UpdateStatus(ctx context.Context, status *model.Status) error {
+	existing, err := s.StatusPrioritiesRepository.GetStatus(ctx, status.ID, *status.UserID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("status not found")
+	}
+	if existing.UserID == nil {
+		return errors.New("default statuses cannot be changed")
+	}
+
+	if err := s.validateStatus(ctx, status); err != nil {
+		return err
+	}
+	return s.StatusPrioritiesRepository.UpdateStatus(ctx, status)
+}
+
+func (s *statusPriorityService) DeleteStatus(ctx context.Context, statusID int, userID string) error {
+	existing, err := s.StatusPrioritiesRepository.GetStatus(ctx, statusID, userID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("status not found")
+	}
+	if existing.UserID == nil {
+		return errors.New("default statuses cannot be deleted")
+	}
+
+	inUse, err := s.StatusPrioritiesRepository.StatusInUse(ctx, statusID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("status is still used by tasks")
+	}
+	return s.StatusPrioritiesRepository.DeleteStatus(ctx, statusID, userID)
+}
+
+func (s *statusPriorityService) ListPriorities(ctx context.Context, userID string) ([]*model.Priority, error) {
+	return s.StatusPrioritiesRepository.ListPriorities(ctx, userID)
+}
+
+func (s *statusPriorityService) validatePriority(ctx context.Context, priority *model.Priority) error {
+	priority.Name = strings.TrimSpace(priority.Name)
+	if priority.UserID == nil || *priority.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if priority.Name == "" {
+		return errors.New("priority name cannot be empty")
+	}
+	if priority.Position < 0 {
+		return errors.New("position cannot be negative")
+	}
+
+	color, err := normalizeColor(priority.Color)
+	if err != nil {
+		return err
+	}
+	priority.Color = color
+
+	taken, err := s.StatusPrioritiesRepository.PriorityNameTaken(ctx, priority.Name, *priority.UserID, priority.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("priority already exists")
+	}
+	return nil
+}
+
+func (s *statusPriorityService) CreatePriority(ctx context.Context, priority *model.Priority) error {
+	if err := s.validatePriority(ctx, priority); err != nil {
+		return err
+	}
+	return s.StatusPrioritiesRepository.CreatePriority(ctx, priority)
+}
+
+func (s *statusPriorityService) UpdatePriority(ctx context.Context, priority *model.Priority) error {
+	existing, err := s.StatusPrioritiesRepository.GetPriority(ctx, priority.ID, *priority.UserID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("priority not found")
+	}
+	if existing.UserID == nil {
+		return errors.New("default priorities cannot be changed")
+	}
+
+	if err := s.validatePriority(ctx, priority); err != nil {
+		return err
+	}
+	return s.StatusPrioritiesRepository.UpdatePriority(ctx, priority)
+}
+
+func (s *statusPriorityService) DeletePriority(ctx context.Context, priorityID int, userID string) error {
+	existing, err := s.StatusPrioritiesRepository.GetPriority(ctx, priorityID, userID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("priority not found")
+	}
+	if existing.UserID == nil {
+		return errors.New("default priorities cannot be deleted")
+	}
+
+	inUse, err := s.StatusPrioritiesRepository.PriorityInUse(ctx, priorityID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("priority is still used by tasks")
+	}
+	return s.StatusPrioritiesRepository.DeletePriority(ctx, priorityID, userID)
+}
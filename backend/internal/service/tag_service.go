@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type TagService interface {
+	List(ctx context.Context, userID string) ([]*model.Tag, error)
+	Create(ctx context.Context, userID, name string) (*model.Tag, error)
+	Rename(ctx context.Context, tagID, userID, name string) (*model.Tag, error)
+	Delete(ctx context.Context, tagID, userID string) error
+}
+
+type tagService struct {
+	TagRepository repository.TagRepository
+}
+
+func NewTagService(tagRepository repository.TagRepository) TagService {
+	return &tagService{TagRepository: tagRepository}
+}
+
+const maxTagLength = 50
+
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" {
+		return "", errors.New("tag name cannot be empty")
+	}
+	if len(name) > maxTagLength {
+		return "", errors.New("tag name is too long")
+	}
+	return name, nil
+}
+
+func (s *tagService) List(ctx context.Context, userID string) ([]*model.Tag, error) {
+	return s.TagRepository.ListByUser(ctx, userID)
+}
+
+func (s *tagService) Create(ctx context.Context, userID, name string) (*model.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.TagRepository.GetByName(ctx, name, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("tag already exists")
+	}
+
+	tag := &model.Tag{UserID: userID, Name: name}
+	if err := s.TagRepository.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) Rename(ctx context.Context, tagID, userID, name string) (*model.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := s.TagRepository.GetByID(ctx, tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, errors.New("tag not found")
+	}
+
+	existing, err := s.TagRepository.GetByName(ctx, name, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != tagID {
+		return nil, errors.New("tag already exists")
+	}
+
+	tag.Name = name
+	if err := s.TagRepository.Rename(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) Delete(ctx context.Context, tagID, userID string) error {
+	err := s.TagRepository.Delete(ctx, tagID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("tag not found")
+	}
+	return err
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+const (
+	BulkSetStatus    = "set_status"
+	BulkSetPriority  = "set_priority"
+	BulkMoveCategory = "move_category"
+	BulkSetDueDate   = "set_due_date"
+	BulkShiftDueDate = "shift_due_date"
+	BulkDelete       = "delete"
+	BulkArchive      = "archive"
+	BulkUnarchive    = "unarchive"
+)
+
+const (
+	// BulkModeAtomic applies every item or none of them.
+	BulkModeAtomic = "atomic"
+	// BulkModeBestEffort keeps the items that succeed.
+	BulkModeBestEffort = "best_effort"
+)
+
+const (
+	BulkItemOK         = "ok"
+	BulkItemFailed     = "failed"
+	BulkItemRolledBack = "rolled_back"
+)
+
+const maxBulkTasks = 500
+
+var ErrInvalidBulkRequest = errors.New("invalid bulk request")
+
+// errBulkRolledBack aborts the transaction of an atomic run with failures.
+var errBulkRolledBack = errors.New("bulk operation rolled back")
+
+// BulkTaskOp is one action applied to many tasks. Only the fields the
+// action needs are read.
+type BulkTaskOp struct {
+	TaskIDs       []string
+	Action        string
+	Mode          string
+	StatusID      int
+	PriorityID    int
+	CategoryID    *string
+	CategoryName  string
+	DueDate       *time.Time
+	DueAllDay     bool
+	ShiftDays     int
+	Children      repository.ChildDeleteMode
+	StatusComment string
+	Force         bool
+
+	// loc is the user's timezone, in which timed due dates are shifted.
+	loc *time.Location
+}
+
+type BulkItemResult struct {
+	TaskID string `json:"task_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkTaskResult struct {
+	Action    string           `json:"action"`
+	Mode      string           `json:"mode"`
+	Applied   bool             `json:"applied"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+func validateBulkOp(op *BulkTaskOp) error {
+	if op.Mode == "" {
+		op.Mode = BulkModeAtomic
+	}
+	if op.Mode != BulkModeAtomic && op.Mode != BulkModeBestEffort {
+		return fmt.Errorf("%w: mode must be atomic or best_effort", ErrInvalidBulkRequest)
+	}
+
+	ids := make([]string, 0, len(op.TaskIDs))
+	seen := map[string]bool{}
+	for _, id := range op.TaskIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return fmt.Errorf("%w: task IDs cannot be empty", ErrInvalidBulkRequest)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: ids is required", ErrInvalidBulkRequest)
+	}
+	if len(ids) > maxBulkTasks {
+		return fmt.Errorf("%w: at most %d tasks per request", ErrInvalidBulkRequest, maxBulkTasks)
+	}
+	op.TaskIDs = ids
+
+	switch op.Action {
+	case BulkSetStatus:
+		if op.StatusID <= 0 {
+			return fmt.Errorf("%w: status_id is required", ErrInvalidBulkRequest)
+		}
+	case BulkSetPriority:
+		if op.PriorityID <= 0 {
+			return fmt.Errorf("%w: priority_id is required", ErrInvalidBulkRequest)
+		}
+	case BulkShiftDueDate:
+		if op.ShiftDays == 0 {
+			return fmt.Errorf("%w: days must be a non-zero number", ErrInvalidBulkRequest)
+		}
+	case BulkDelete:
+		switch op.Children {
+		case "", repository.DeleteChildrenCascade, repository.DeleteChildrenReparent:
+		default:
+			return ErrInvalidChildDeleteMode
+		}
+	case BulkMoveCategory:
+		if op.CategoryID != nil && strings.TrimSpace(*op.CategoryID) == "" {
+			op.CategoryID = nil
+		}
+	case BulkSetDueDate, BulkArchive, BulkUnarchive:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidBulkRequest, op.Action)
+	}
+	return nil
+}
+
+// Bulk applies one action to many tasks in a single transaction. Each task
+// runs in its own savepoint and goes through the same checks as a single
+// update, so one failure never leaves a task half-written. In atomic mode
+// any failure rolls the whole batch back.
+func (s *taskService) Bulk(ctx context.Context, userID string, op BulkTaskOp) (*BulkTaskResult, error) {
+	if err := validateBulkOp(&op); err != nil {
+		return nil, err
+	}
+
+	result := &BulkTaskResult{
+		Action:  op.Action,
+		Mode:    op.Mode,
+		Results: make([]BulkItemResult, 0, len(op.TaskIDs)),
+	}
+
+	if op.Action == BulkShiftDueDate {
+		loc, err := userLocation(ctx, s.UserRepository, userID)
+		if err != nil {
+			return nil, err
+		}
+		op.loc = loc
+	}
+
+	err := s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if op.Action == BulkMoveCategory && strings.TrimSpace(op.CategoryName) != "" {
+			id, err := s.ensureCategory(ctx, userID, op.CategoryName)
+			if err != nil {
+				return err
+			}
+			op.CategoryID = id
+		}
+
+		// trashed holds the ids an earlier delete in the batch already took
+		// with it, such as a subtask listed next to its parent.
+		trashed := map[string]bool{}
+		for _, id := range op.TaskIDs {
+			if trashed[id] {
+				result.Results = append(result.Results, BulkItemResult{TaskID: id, Status: BulkItemOK})
+				result.Succeeded++
+				continue
+			}
+			var covered []string
+			err := s.Transactor.WithinSavepoint(ctx, func(ctx context.Context) error {
+				if op.Action == BulkDelete {
+					var err error
+					covered, err = s.bulkDelete(ctx, id, userID, op.Children)
+					return err
+				}
+				return s.bulkApply(ctx, id, userID, op)
+			})
+			if err == nil {
+				for _, c := range covered {
+					trashed[c] = true
+				}
+			}
+			item := BulkItemResult{TaskID: id, Status: BulkItemOK}
+			if err != nil {
+				item.Status = BulkItemFailed
+				item.Error = bulkItemError(err)
+				result.Failed++
+			} else {
+				result.Succeeded++
+			}
+			result.Results = append(result.Results, item)
+		}
+
+		if op.Mode == BulkModeAtomic && result.Failed > 0 {
+			return errBulkRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkRolledBack) {
+		for i := range result.Results {
+			if result.Results[i].Status == BulkItemOK {
+				result.Results[i].Status = BulkItemRolledBack
+			}
+		}
+		result.Succeeded = 0
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Applied = true
+	return result, nil
+}
+
+func bulkItemError(err error) string {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound.Error()
+	}
+	return err.Error()
+}
+
+// bulkDelete trashes a task and returns the ids it took with it: the task
+// itself and, when cascading, its whole subtree.
+func (s *taskService) bulkDelete(ctx context.Context, taskID, userID string, mode repository.ChildDeleteMode) ([]string, error) {
+	covered := []string{taskID}
+	if mode == "" || mode == repository.DeleteChildrenCascade {
+		nodes, err := s.TaskRepository.ListDescendants(ctx, taskID, userID, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodes {
+			covered = append(covered, n.Task.ID)
+		}
+	}
+	if err := s.Delete(ctx, taskID, userID, mode, nil); err != nil {
+		return nil, err
+	}
+	return covered, nil
+}
+
+func (s *taskService) bulkApply(ctx context.Context, taskID, userID string, op BulkTaskOp) error {
+	switch op.Action {
+	case BulkArchive:
+		return s.setArchived(ctx, taskID, userID, true)
+	case BulkUnarchive:
+		return s.setArchived(ctx, taskID, userID, false)
+	}
+
+	current, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrTaskNotFound
+	}
+
+	task := *current
+	task.Tags = nil
+	switch op.Action {
+	case BulkSetStatus:
+		task.StatusID = op.StatusID
+	case BulkSetPriority:
+		task.PriorityID = op.PriorityID
+	case BulkMoveCategory:
+		task.CategoryID = op.CategoryID
+	case BulkSetDueDate:
+		task.DueDate, task.DueAllDay = op.DueDate, op.DueAllDay
+	case BulkShiftDueDate:
+		if task.DueDate == nil {
+			return errors.New("task has no due date to shift")
+		}
+		due := task.DueDate.UTC().AddDate(0, 0, op.ShiftDays)
+		if !task.DueAllDay {
+			// Keep the local time of day across daylight saving changes.
+			due = task.DueDate.In(op.loc).AddDate(0, 0, op.ShiftDays)
+		}
+		task.DueDate = &due
+	}
+
+	return s.update(ctx, current, &task, "", TaskUpdateOptions{
+		StatusComment: op.StatusComment,
+		Force:         op.Force,
+	}, true)
+}
+
+// setArchived archives or unarchives a task. Archived tasks keep their data
+// but drop out of the default task list.
+func (s *taskService) setArchived(ctx context.Context, taskID, userID string, archived bool) error {
+	current, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrTaskNotFound
+	}
+	if (current.ArchivedAt != nil) == archived {
+		return nil
+	}
+
+	task := *current
+	task.ArchivedAt = nil
+	action := model.TaskEventUnarchived
+	if archived {
+		now := time.Now()
+		task.ArchivedAt = &now
+		action = model.TaskEventArchived
+	}
+
+	return s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.TaskRepository.Patch(ctx, &task, []string{"archived_at"})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskModified
+		}
+		if err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, action, userID, current, &task)
+	})
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+// auditFields lists the task fields tracked by the audit trail. Derived and
+// server-managed values (blocked, progress, timestamps) are left out.
+type auditFields struct {
+	ParentID    *string    `json:"parent_id"`
+	CategoryID  *string    `json:"category_id"`
+	StatusID    int        `json:"status_id"`
+	PriorityID  int        `json:"priority_id"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+	// DueAllDay is nil in snapshots taken before due times existed, when
+	// every due date was all-day.
+	DueAllDay   *bool      `json:"due_all_day"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Recurrence  *string    `json:"recurrence"`
+	Tags        []string   `json:"tags"`
+}
+
+func snapshotOf(t *model.Task) auditFields {
+	tags := append([]string{}, t.Tags...)
+	sort.Strings(tags)
+	return auditFields{
+		ParentID:    t.ParentID,
+		CategoryID:  t.CategoryID,
+		StatusID:    t.StatusID,
+		PriorityID:  t.PriorityID,
+		Title:       t.Title,
+		Description: t.Description,
+		DueDate:     t.DueDate,
+		DueAllDay:   &t.DueAllDay,
+		CompletedAt: t.CompletedAt,
+		Recurrence:  t.Recurrence,
+		Tags:        tags,
+	}
+}
+
+func auditSnapshot(t *model.Task) map[string]json.RawMessage {
+	out := map[string]json.RawMessage{}
+	if t == nil {
+		return out
+	}
+	b, _ := json.Marshal(snapshotOf(t))
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+// diffTask returns the audited fields that differ between before and after.
+// Either side may be nil for creates and deletes; fields that are empty on
+// both sides are omitted.
+func diffTask(before, after *model.Task) map[string]model.FieldChange {
+	from, to := auditSnapshot(before), auditSnapshot(after)
+	null := json.RawMessage("null")
+
+	fields := map[string]bool{}
+	for field := range from {
+		fields[field] = true
+	}
+	for field := range to {
+		fields[field] = true
+	}
+
+	changes := map[string]model.FieldChange{}
+	for field := range fields {
+		f, ok := from[field]
+		if !ok {
+			f = null
+		}
+		t, ok := to[field]
+		if !ok {
+			t = null
+		}
+		if !bytes.Equal(f, t) {
+			changes[field] = model.FieldChange{From: f, To: t}
+		}
+	}
+	return changes
+}
+
+// recordEvent appends an audit event for the mutation from before to after.
+// Updates that change no audited field are not recorded.
+func (s *taskService) recordEvent(ctx context.Context, action string, userID string, before, after *model.Task) error {
+	changes := diffTask(before, after)
+	if action == model.TaskEventUpdated && len(changes) == 0 {
+		return nil
+	}
+
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+	return s.TaskEventRepository.Record(ctx, &model.TaskEvent{
+		TaskID:  subject.ID,
+		UserID:  userID,
+		Action:  action,
+		Changes: changes,
+	})
+}
+
+func (s *taskService) History(ctx context.Context, taskID, userID string) ([]*model.TaskEvent, error) {
+	events, err := s.TaskEventRepository.ListByTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	// Tasks created before the audit trail existed have no events yet.
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return events, nil
+}
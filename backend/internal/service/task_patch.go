@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+)
+
+// TaskPatch is a JSON Merge Patch (RFC 7396) document for a task, keyed by
+// the task's JSON field names. Absent fields are left untouched and null
+// clears a field. category_name and tags are accepted as on create.
+type TaskPatch map[string]json.RawMessage
+
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// decodeNullable decodes raw into dst, or sets dst to nil for a null value.
+func decodeNullable[T any](field string, raw json.RawMessage, dst **T) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("invalid value for %s", field)
+	}
+	*dst = &v
+	return nil
+}
+
+// decodeRequired decodes raw into dst, refusing null for fields that
+// cannot be cleared.
+func decodeRequired[T any](field string, raw json.RawMessage, dst *T) error {
+	if isNull(raw) {
+		return fmt.Errorf("%s cannot be null", field)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("invalid value for %s", field)
+	}
+	return nil
+}
+
+// applyTaskPatch merges patch into task and returns the category name to
+// resolve, if one was sent. due_all_day says how to read due_date; without
+// a due_date it turns the current due date into an all-day or timed one.
+func applyTaskPatch(task *model.Task, patch TaskPatch) (string, error) {
+	var allDay *bool
+	if raw, ok := patch["due_all_day"]; ok {
+		var v bool
+		if err := decodeRequired("due_all_day", raw, &v); err != nil {
+			return "", err
+		}
+		allDay = &v
+	}
+
+	var categoryName string
+	for field, raw := range patch {
+		var err error
+		switch field {
+		case "parent_id":
+			err = decodeNullable(field, raw, &task.ParentID)
+		case "category_id":
+			err = decodeNullable(field, raw, &task.CategoryID)
+		case "category_name":
+			err = decodeRequired(field, raw, &categoryName)
+		case "status_id":
+			err = decodeRequired(field, raw, &task.StatusID)
+		case "priority_id":
+			err = decodeRequired(field, raw, &task.PriorityID)
+		case "title":
+			err = decodeRequired(field, raw, &task.Title)
+		case "description":
+			err = decodeNullable(field, raw, &task.Description)
+		case "recurrence":
+			err = decodeNullable(field, raw, &task.Recurrence)
+		case "due_date":
+			var due *string
+			if err = decodeNullable(field, raw, &due); err == nil {
+				task.DueDate, task.DueAllDay = nil, false
+				if due != nil && *due != "" {
+					t, isAllDay, perr := ParseDueDateAs(*due, allDay)
+					if perr != nil {
+						return "", perr
+					}
+					task.DueDate, task.DueAllDay = &t, isAllDay
+				}
+			}
+		case "due_all_day":
+			if _, ok := patch["due_date"]; !ok && task.DueDate != nil {
+				task.DueAllDay = *allDay
+				if *allDay {
+					due := asAllDay(*task.DueDate)
+					task.DueDate = &due
+				}
+			}
+		case "tags":
+			task.Tags = []string{}
+			if !isNull(raw) {
+				err = decodeRequired(field, raw, &task.Tags)
+			}
+		default:
+			return "", fmt.Errorf("unknown or read-only field %q", field)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	task.Title = strings.TrimSpace(task.Title)
+	if task.Title == "" {
+		return "", errors.New("task title cannot be empty")
+	}
+	return categoryName, nil
+}
+
+// Patch applies a merge patch to a task. Only the columns that end up
+// changed are written.
+func (s *taskService) Patch(ctx context.Context, taskID, userID string, patch TaskPatch, opts TaskUpdateOptions) (*model.Task, error) {
+	current, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	task := *current
+	// nil tags leave the task's tags alone unless the patch sets them.
+	task.Tags = nil
+	categoryName, err := applyTaskPatch(&task, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.update(ctx, current, &task, categoryName, opts, true); err != nil {
+		return nil, err
+	}
+	return s.TaskRepository.GetByID(ctx, taskID, userID)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/quickadd"
+)
+
+const maxQuickAddLength = 500
+
+var ErrInvalidQuickAdd = errors.New("invalid quick add")
+
+// QuickAddResult pairs what was read from the text with the task built from
+// it. On a dry run the task is not saved and has no ID.
+type QuickAddResult struct {
+	Parsed *quickadd.Result `json:"parsed"`
+	Task   *model.Task      `json:"task"`
+	DryRun bool             `json:"dry_run"`
+}
+
+// QuickAdd parses a one-line description and creates the task through
+// Create, starting in the first open status. Without a !priority marker the
+// middle one of the user's priorities is used. Relative dates count from
+// today in loc, or in the user's timezone when loc is nil.
+func (s *taskService) QuickAdd(ctx context.Context, userID, text string, loc *time.Location, dryRun bool) (*QuickAddResult, error) {
+	if len(text) > maxQuickAddLength {
+		return nil, fmt.Errorf("%w: text is longer than %d characters", ErrInvalidQuickAdd, maxQuickAddLength)
+	}
+	if loc == nil {
+		var err error
+		if loc, err = userLocation(ctx, s.UserRepository, userID); err != nil {
+			return nil, err
+		}
+	}
+	parsed, err := quickadd.Parse(text, localToday(loc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuickAdd, err)
+	}
+	for _, segment := range strings.Split(parsed.Category, "/") {
+		if parsed.Category != "" && strings.TrimSpace(segment) == "" {
+			return nil, fmt.Errorf("%w: category path cannot contain empty segments", ErrInvalidQuickAdd)
+		}
+	}
+
+	priorityID, err := s.quickAddPriority(ctx, userID, parsed.Priority)
+	if err != nil {
+		return nil, err
+	}
+	statusID, err := s.initialStatusID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &model.Task{
+		UserID:     userID,
+		StatusID:   statusID,
+		PriorityID: priorityID,
+		Title:      parsed.Title,
+		DueDate:    parsed.DueDate,
+		DueAllDay:  parsed.DueDate != nil,
+		Tags:       []string{},
+	}
+	if parsed.Recurrence != "" {
+		task.Recurrence = &parsed.Recurrence
+	}
+
+	result := &QuickAddResult{Parsed: parsed, Task: task, DryRun: dryRun}
+	if dryRun {
+		if parsed.Category != "" {
+			task.CategoryName = &parsed.Category
+		}
+		return result, nil
+	}
+	if err := s.Create(ctx, task, parsed.Category); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *taskService) quickAddPriority(ctx context.Context, userID, name string) (int, error) {
+	priorities, err := s.StatusPrioritiesRepository.ListPriorities(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(priorities) == 0 {
+		return 0, fmt.Errorf("%w: no priorities to choose from", ErrInvalidQuickAdd)
+	}
+	if name == "" {
+		return priorities[(len(priorities)-1)/2].ID, nil
+	}
+	for _, p := range priorities {
+		if strings.EqualFold(p.Name, name) {
+			return p.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown priority %q", ErrInvalidQuickAdd, name)
+}
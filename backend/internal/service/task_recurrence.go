@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/recurrence"
+)
+
+// Edit scopes for recurring tasks: ScopeThis changes only the occurrence
+// being edited, ScopeFuture also reschedules the series from it onwards.
+const (
+	ScopeThis   = "this"
+	ScopeFuture = "future"
+)
+
+// normalizeRecurrence validates task.Recurrence and rewrites it in canonical
+// form. An empty rule clears it.
+func normalizeRecurrence(task *model.Task) error {
+	if task.Recurrence == nil || strings.TrimSpace(*task.Recurrence) == "" {
+		task.Recurrence = nil
+		return nil
+	}
+
+	rule, err := recurrence.Parse(*task.Recurrence)
+	if err != nil {
+		return err
+	}
+	if task.DueDate == nil {
+		return errors.New("recurring tasks need a due date")
+	}
+
+	canonical := rule.String()
+	task.Recurrence = &canonical
+	return nil
+}
+
+func sameRecurrence(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyRecurrenceScope carries the server-managed series fields over from
+// current and decides which date the series continues from.
+func applyRecurrenceScope(task, current *model.Task, scope string) error {
+	task.SeriesID = current.SeriesID
+	task.Occurrence = current.Occurrence
+
+	switch scope {
+	case "", ScopeThis:
+		if current.Recurrence != nil && !sameRecurrence(task.Recurrence, current.Recurrence) {
+			return errors.New("changing the recurrence applies to all future occurrences; use scope=future")
+		}
+		task.ScheduledFor = current.ScheduledFor
+		if task.ScheduledFor == nil {
+			task.ScheduledFor = task.DueDate
+		}
+	case ScopeFuture:
+		task.ScheduledFor = task.DueDate
+	default:
+		return errors.New("scope must be this or future")
+	}
+	return nil
+}
+
+func (s *taskService) initialStatusID(ctx context.Context, userID string) (int, error) {
+	statuses, err := s.StatusPrioritiesRepository.ListStatuses(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	for _, st := range statuses {
+		if !st.IsTerminal {
+			return st.ID, nil
+		}
+	}
+	return 0, errors.New("no open status to start the next occurrence in")
+}
+
+// spawnNextOccurrence creates the occurrence that follows a recurring task
+// which has just been completed. It is a no-op once the series has ended or
+// when the next occurrence already exists (the task was reopened and
+// completed again).
+func (s *taskService) spawnNextOccurrence(ctx context.Context, task *model.Task) error {
+	if task.Recurrence == nil || task.DueDate == nil {
+		return nil
+	}
+	rule, err := recurrence.Parse(*task.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	base := *task.DueDate
+	if task.ScheduledFor != nil {
+		base = *task.ScheduledFor
+	}
+	// All-day dates step through UTC calendar days; timed ones through the
+	// user's zone so the local time of day survives daylight saving changes.
+	if task.DueAllDay {
+		base = base.UTC()
+	} else {
+		loc, err := userLocation(ctx, s.UserRepository, task.UserID)
+		if err != nil {
+			return err
+		}
+		base = base.In(loc)
+	}
+	next, ok := rule.Next(base, task.Occurrence)
+	if !ok {
+		return nil
+	}
+
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+	exists, err := s.TaskRepository.OccurrenceExists(ctx, seriesID, task.Occurrence+1, task.UserID)
+	if err != nil || exists {
+		return err
+	}
+
+	statusID, err := s.initialStatusID(ctx, task.UserID)
+	if err != nil {
+		return err
+	}
+
+	nextTask := &model.Task{
+		UserID:       task.UserID,
+		ParentID:     task.ParentID,
+		CategoryID:   task.CategoryID,
+		StatusID:     statusID,
+		PriorityID:   task.PriorityID,
+		Title:        task.Title,
+		Description:  task.Description,
+		DueDate:      &next,
+		DueAllDay:    task.DueAllDay,
+		Recurrence:   task.Recurrence,
+		SeriesID:     &seriesID,
+		Occurrence:   task.Occurrence + 1,
+		ScheduledFor: &next,
+		Tags:         task.Tags,
+	}
+	if err := s.TaskRepository.Create(ctx, nextTask); err != nil {
+		return err
+	}
+	if err := s.saveTags(ctx, nextTask); err != nil {
+		return err
+	}
+	if err := s.recordEvent(ctx, model.TaskEventCreated, task.UserID, nil, nextTask); err != nil {
+		return err
+	}
+	if err := s.saveVersion(ctx, task.UserID, nil, nextTask, nil); err != nil {
+		return err
+	}
+	reminderAt, err := reminderBase(ctx, s.UserRepository, nextTask)
+	if err != nil {
+		return err
+	}
+	return s.ReminderRepository.CopyOffsets(ctx, task.ID, nextTask.ID, reminderAt)
+}
+
+func dueEqual(a, b *model.Task) bool {
+	if (a.DueDate == nil) != (b.DueDate == nil) {
+		return false
+	}
+	return a.DueAllDay == b.DueAllDay && (a.DueDate == nil || a.DueDate.Equal(*b.DueDate))
+}
+
+// rescheduleReminders keeps the task's offset reminders in step with its
+// due date.
+func (s *taskService) rescheduleReminders(ctx context.Context, task *model.Task) error {
+	base, err := reminderBase(ctx, s.UserRepository, task)
+	if err != nil {
+		return err
+	}
+	return s.ReminderRepository.RescheduleOffsets(ctx, task.ID, base)
+}
+
+// updateSeries copies task's series-wide fields onto the later open
+// occurrences and records each change in the history and versions, like a
+// direct edit of that occurrence.
+func (s *taskService) updateSeries(ctx context.Context, task *model.Task) error {
+	before, err := s.TaskRepository.UpdateSeries(ctx, task)
+	if err != nil {
+		return err
+	}
+	for _, b := range before {
+		after := *b
+		after.Title = task.Title
+		after.Description = task.Description
+		after.PriorityID = task.PriorityID
+		after.CategoryID = task.CategoryID
+		after.Recurrence = task.Recurrence
+		after.Revision++
+		if err := s.recordEvent(ctx, model.TaskEventUpdated, task.UserID, b, &after); err != nil {
+			return err
+		}
+		if err := s.saveVersion(ctx, task.UserID, b, &after, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,54 +2,275 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/liaa-aa/task-manager-project/backend/internal/model"
 	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
 )
 
 type TaskService interface {
-	List(ctx context.Context, userID string) ([]*model.Task, error)
+	List(ctx context.Context, userID string, filter repository.TaskFilter) (*repository.TaskPage, error)
 	Get(ctx context.Context, taskID, userID string) (*model.Task, error)
 	Create(ctx context.Context, task *model.Task, categoryName string) error
-	Update(ctx context.Context, task *model.Task, categoryName string) error
-	Delete(ctx context.Context, taskID, userID string) error
+	Update(ctx context.Context, task *model.Task, categoryName string, opts TaskUpdateOptions) error
+	Patch(ctx context.Context, taskID, userID string, patch TaskPatch, opts TaskUpdateOptions) (*model.Task, error)
+	Delete(ctx context.Context, taskID, userID string, mode repository.ChildDeleteMode, ifMatch []int) error
+	Children(ctx context.Context, taskID, userID string, depth int) (*TaskChildren, error)
+	History(ctx context.Context, taskID, userID string) ([]*model.TaskEvent, error)
+	Versions(ctx context.Context, taskID, userID string) ([]*model.TaskVersion, error)
+	Revert(ctx context.Context, taskID, userID string, version int, statusComment string) (*model.Task, error)
+	Bulk(ctx context.Context, userID string, op BulkTaskOp) (*BulkTaskResult, error)
+	QuickAdd(ctx context.Context, userID, text string, loc *time.Location, dryRun bool) (*QuickAddResult, error)
+}
+
+type TaskChildren struct {
+	TaskID   string              `json:"task_id"`
+	Progress *model.TaskProgress `json:"progress"`
+	Children []*model.TaskNode   `json:"children"`
+}
+
+// TaskUpdateOptions carries request data that accompanies an update but is
+// not stored on the task itself.
+type TaskUpdateOptions struct {
+	StatusComment string
+	Force         bool
+	Scope         string
+	// IfMatch lists the revisions the caller accepts; nil skips the check.
+	IfMatch []int
+
+	// revertedFrom is set by Revert so the new version records its origin.
+	revertedFrom *int
 }
 
 type taskService struct {
 	TaskRepository             repository.TaskRepository
 	StatusPrioritiesRepository repository.StatusPrioritiesRepository
 	CategoryRepository         repository.CategoryRepository
+	WorkflowRepository         repository.WorkflowRepository
+	DependencyRepository       repository.DependencyRepository
+	TagRepository              repository.TagRepository
+	TaskEventRepository        repository.TaskEventRepository
+	TaskVersionRepository      repository.TaskVersionRepository
+	UserRepository             repository.UserRepository
+	ReminderRepository         repository.ReminderRepository
+	Transactor                 repository.Transactor
 }
 
 func NewTaskService(
 	taskRepository repository.TaskRepository,
 	statusPrioritiesRepository repository.StatusPrioritiesRepository,
 	categoryRepository repository.CategoryRepository,
+	workflowRepository repository.WorkflowRepository,
+	dependencyRepository repository.DependencyRepository,
+	tagRepository repository.TagRepository,
+	taskEventRepository repository.TaskEventRepository,
+	taskVersionRepository repository.TaskVersionRepository,
+	userRepository repository.UserRepository,
+	reminderRepository repository.ReminderRepository,
+	transactor repository.Transactor,
 ) TaskService {
 	return &taskService{
 		TaskRepository:             taskRepository,
 		StatusPrioritiesRepository: statusPrioritiesRepository,
 		CategoryRepository:         categoryRepository,
+		WorkflowRepository:         workflowRepository,
+		DependencyRepository:       dependencyRepository,
+		TagRepository:              tagRepository,
+		TaskEventRepository:        taskEventRepository,
+		TaskVersionRepository:      taskVersionRepository,
+		UserRepository:             userRepository,
+		ReminderRepository:         reminderRepository,
+		Transactor:                 transactor,
 	}
 }
 
-func (s *taskService) List(ctx context.Context, userID string) ([]*model.Task, error) {
-	return s.TaskRepository.ListByUser(ctx, userID)
+var (
+	ErrInvalidTaskFilter      = errors.New("invalid task filter")
+	ErrInvalidChildDeleteMode = errors.New("children must be cascade or reparent")
+	ErrTaskNotFound           = errors.New("task not found")
+	ErrTaskModified           = errors.New("task has been modified since it was read")
+)
+
+const (
+	defaultTaskPageSize = 50
+	maxTaskPageSize     = 200
+)
+
+var defaultTaskSortOrder = map[string]string{
+	"created_at": "desc",
+	"updated_at": "desc",
+	"due_date":   "asc",
+	"priority":   "desc",
+	"title":      "asc",
+}
+
+func validateTaskFilter(f *repository.TaskFilter) error {
+	if f.Sort == "" {
+		f.Sort = "created_at"
+	}
+	if !repository.IsTaskSortKey(f.Sort) {
+		return fmt.Errorf("%w: sort must be one of due_date, priority, updated_at, title, created_at", ErrInvalidTaskFilter)
+	}
+	switch f.Order {
+	case "":
+		f.Order = defaultTaskSortOrder[f.Sort]
+	case "asc", "desc":
+	default:
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalidTaskFilter)
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultTaskPageSize
+	}
+	if f.Limit < 0 || f.Limit > maxTaskPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTaskFilter, maxTaskPageSize)
+	}
+
+	if f.StatusID != nil && *f.StatusID <= 0 {
+		return fmt.Errorf("%w: invalid status ID", ErrInvalidTaskFilter)
+	}
+	if f.PriorityID != nil && *f.PriorityID <= 0 {
+		return fmt.Errorf("%w: invalid priority ID", ErrInvalidTaskFilter)
+	}
+	if f.CategoryID != nil && strings.TrimSpace(*f.CategoryID) == "" {
+		f.CategoryID = nil
+	}
+	if f.DueFrom != nil && f.DueTo != nil && f.DueFrom.After(*f.DueTo) {
+		return fmt.Errorf("%w: due_from must not be after due_to", ErrInvalidTaskFilter)
+	}
+
+	f.Search = strings.TrimSpace(f.Search)
+	if len(f.Search) > 200 {
+		return fmt.Errorf("%w: search is too long", ErrInvalidTaskFilter)
+	}
+
+	switch f.TagMode {
+	case "":
+		f.TagMode = "any"
+	case "any", "all":
+	default:
+		return fmt.Errorf("%w: tag_mode must be any or all", ErrInvalidTaskFilter)
+	}
+	for i, tag := range f.Tags {
+		name, err := normalizeTagName(tag)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTaskFilter, err)
+		}
+		f.Tags[i] = name
+	}
+
+	if f.Location == nil {
+		f.Location = time.UTC
+	}
+	if f.Today.IsZero() {
+		f.Today = localToday(f.Location)
+	}
+	// is:archived has to see past the default of active tasks only.
+	if f.Query != nil && f.Query.Has("archived") {
+		f.Archived = true
+	}
+
+	if f.Cursor != nil && (f.Cursor.Sort != f.Sort || f.Cursor.Order != f.Order) {
+		return fmt.Errorf("%w: cursor does not match sort", ErrInvalidTaskFilter)
+	}
+	return nil
+}
+
+func (s *taskService) List(ctx context.Context, userID string, filter repository.TaskFilter) (*repository.TaskPage, error) {
+	loc, err := userLocation(ctx, s.UserRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.Location = loc
+	if err := validateTaskFilter(&filter); err != nil {
+		return nil, err
+	}
+	return s.TaskRepository.ListByUser(ctx, userID, filter)
 }
 
 func (s *taskService) Get(ctx context.Context, taskID, userID string) (*model.Task, error) {
-	return s.TaskRepository.GetByID(ctx, taskID, userID)
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil || task == nil {
+		return nil, err
+	}
+
+	task.Progress, err = s.TaskRepository.Progress(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *taskService) Children(ctx context.Context, taskID, userID string, depth int) (*TaskChildren, error) {
+	if depth < 0 {
+		return nil, errors.New("depth cannot be negative")
+	}
+
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+
+	nodes, err := s.TaskRepository.ListDescendants(ctx, taskID, userID, depth)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.TaskRepository.Progress(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes arrive ordered by depth, so every parent is indexed before its children.
+	byID := make(map[string]*model.TaskNode, len(nodes))
+	roots := []*model.TaskNode{}
+	for _, n := range nodes {
+		byID[n.ID] = n
+		if n.Depth == 1 {
+			roots = append(roots, n)
+			continue
+		}
+		if parent, ok := byID[*n.ParentID]; ok {
+			parent.Children = append(parent.Children, n)
+		}
+	}
+
+	return &TaskChildren{TaskID: taskID, Progress: progress, Children: roots}, nil
 }
 
+// ensureCategory resolves a category name to its ID, creating it if needed.
+// A path such as "Work/Backend/API" walks down from the top level and
+// creates any missing levels.
 func (s *taskService) ensureCategory(ctx context.Context, userID, categoryName string) (*string, error) {
-	name := strings.TrimSpace(categoryName)
-	if name == "" {
+	path := strings.TrimSpace(categoryName)
+	if path == "" {
 		return nil, nil
 	}
 
-	existing, err := s.CategoryRepository.GetByName(ctx, name, userID)
+	var parentID *string
+	for _, segment := range strings.Split(path, "/") {
+		name := strings.TrimSpace(segment)
+		if name == "" {
+			return nil, errors.New("category path cannot contain empty segments")
+		}
+
+		id, err := s.ensureCategoryLevel(ctx, userID, name, parentID)
+		if err != nil {
+			return nil, err
+		}
+		parentID = id
+	}
+	return parentID, nil
+}
+
+func (s *taskService) ensureCategoryLevel(ctx context.Context, userID, name string, parentID *string) (*string, error) {
+	existing, err := s.CategoryRepository.GetByName(ctx, name, userID, parentID)
 	if err != nil {
 		return nil, err
 	}
@@ -59,12 +280,13 @@ func (s *taskService) ensureCategory(ctx context.Context, userID, categoryName s
 	}
 
 	c := &model.Category{
-		UserID: userID,
-		Name:   name,
+		UserID:   userID,
+		Name:     name,
+		ParentID: parentID,
 	}
 
 	if err := s.CategoryRepository.Create(ctx, c); err != nil {
-		ex2, err2 := s.CategoryRepository.GetByName(ctx, name, userID)
+		ex2, err2 := s.CategoryRepository.GetByName(ctx, name, userID, parentID)
 		if err2 == nil && ex2 != nil {
 			id := ex2.ID
 			return &id, nil
@@ -76,106 +298,382 @@ func (s *taskService) ensureCategory(ctx context.Context, userID, categoryName s
 	return &id, nil
 }
 
-func (s *taskService) Create(ctx context.Context, task *model.Task, categoryName string) error {
-	task.Title = strings.TrimSpace(task.Title)
+// ensureTags resolves tag names to IDs, auto-creating missing tags the way
+// ensureCategory does. It also returns the names as stored.
+func (s *taskService) ensureTags(ctx context.Context, userID string, names []string) ([]string, []string, error) {
+	ids := []string{}
+	stored := []string{}
+	seen := map[string]bool{}
+	for _, raw := range names {
+		name, err := normalizeTagName(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
 
-	if task.UserID == "" {
-		return errors.New("user ID cannot be empty")
+		t := &model.Tag{UserID: userID, Name: name}
+		if err := s.TagRepository.Ensure(ctx, t); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, t.ID)
+		stored = append(stored, t.Name)
 	}
-	if task.Title == "" {
-		return errors.New("task title cannot be empty")
+	return ids, stored, nil
+}
+
+// saveTags stores task.Tags when the caller supplied them; a nil slice
+// leaves the task's tags untouched.
+func (s *taskService) saveTags(ctx context.Context, task *model.Task) error {
+	if task.Tags == nil {
+		return nil
+	}
+	ids, names, err := s.ensureTags(ctx, task.UserID, task.Tags)
+	if err != nil {
+		return err
+	}
+	if err := s.TagRepository.SetTaskTags(ctx, task.ID, ids); err != nil {
+		return err
+	}
+	task.Tags = names
+	return nil
+}
+
+// validateTask checks the fields shared by Create and Update and returns the
+// task's resolved status. current is nil on create; an update may keep the
+// category it already has even while that category is in the trash.
+func (s *taskService) validateTask(ctx context.Context, task, current *model.Task, categoryName string) (*model.Status, error) {
+	if task.ParentID != nil && *task.ParentID == "" {
+		task.ParentID = nil
+	}
+	if task.ParentID != nil {
+		if err := s.validateParent(ctx, task); err != nil {
+			return nil, err
+		}
 	}
 
 	if strings.TrimSpace(categoryName) != "" {
 		newCatID, err := s.ensureCategory(ctx, task.UserID, categoryName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		task.CategoryID = newCatID
 	}
 
-	ok, err := s.StatusPrioritiesRepository.StatusExist(ctx, task.StatusID, task.UserID)
+	status, err := s.StatusPrioritiesRepository.GetStatus(ctx, task.StatusID, task.UserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !ok {
-		return errors.New("invalid status ID")
+	if status == nil {
+		return nil, errors.New("invalid status ID")
 	}
 
-	ok, err = s.StatusPrioritiesRepository.PrioritiesExist(ctx, task.PriorityID, task.UserID)
+	ok, err := s.StatusPrioritiesRepository.PrioritiesExist(ctx, task.PriorityID, task.UserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !ok {
-		return errors.New("invalid priority ID")
+		return nil, errors.New("invalid priority ID")
 	}
 
-	if task.CategoryID != nil && *task.CategoryID != "" {
+	keptCategory := current != nil && sameCategory(task.CategoryID, current.CategoryID)
+	if task.CategoryID != nil && *task.CategoryID != "" && !keptCategory {
 		owned, err := s.CategoryRepository.ExistOwned(ctx, *task.CategoryID, task.UserID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !owned {
-			return errors.New("category does not belong to user")
+			return nil, errors.New("category does not belong to user")
 		}
 	}
 
-	return s.TaskRepository.Create(ctx, task)
+	return status, nil
 }
 
-func (s *taskService) Update(ctx context.Context, task *model.Task, categoryName string) error {
+func sameCategory(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s *taskService) validateParent(ctx context.Context, task *model.Task) error {
+	parentID := *task.ParentID
+	if parentID == task.ID {
+		return errors.New("a task cannot be its own parent")
+	}
+
+	parent, err := s.TaskRepository.GetByID(ctx, parentID, task.UserID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return errors.New("parent task not found")
+	}
+
+	if task.ID != "" {
+		cycle, err := s.TaskRepository.IsDescendant(ctx, task.ID, parentID, task.UserID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return errors.New("parent task cannot be one of the task's own subtasks")
+		}
+	}
+	return nil
+}
+
+func (s *taskService) Create(ctx context.Context, task *model.Task, categoryName string) error {
 	task.Title = strings.TrimSpace(task.Title)
 
 	if task.UserID == "" {
 		return errors.New("user ID cannot be empty")
 	}
-	if task.ID == "" {
-		return errors.New("task ID cannot be empty")
-	}
 	if task.Title == "" {
 		return errors.New("task title cannot be empty")
 	}
 
-	if strings.TrimSpace(categoryName) != "" {
-		newCatID, err := s.ensureCategory(ctx, task.UserID, categoryName)
-		if err != nil {
+	status, err := s.validateTask(ctx, task, nil, categoryName)
+	if err != nil {
+		return err
+	}
+	if err := normalizeRecurrence(task); err != nil {
+		return err
+	}
+
+	task.CompletedAt = nil
+	if status.IsTerminal {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	task.SeriesID = nil
+	task.Occurrence = 1
+	task.ScheduledFor = task.DueDate
+
+	return s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepository.Create(ctx, task); err != nil {
 			return err
 		}
-		task.CategoryID = newCatID
+		if task.Tags == nil {
+			task.Tags = []string{}
+		}
+		if err := s.saveTags(ctx, task); err != nil {
+			return err
+		}
+		if err := s.recordEvent(ctx, model.TaskEventCreated, task.UserID, nil, task); err != nil {
+			return err
+		}
+		return s.saveVersion(ctx, task.UserID, nil, task, nil)
+	})
+}
+
+func (s *taskService) Update(ctx context.Context, task *model.Task, categoryName string, opts TaskUpdateOptions) error {
+	task.Title = strings.TrimSpace(task.Title)
+
+	if task.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if task.ID == "" {
+		return errors.New("task ID cannot be empty")
+	}
+	if task.Title == "" {
+		return errors.New("task title cannot be empty")
 	}
 
-	ok, err := s.StatusPrioritiesRepository.StatusExist(ctx, task.StatusID, task.UserID)
+	current, err := s.TaskRepository.GetByID(ctx, task.ID, task.UserID)
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return errors.New("invalid status ID")
+	if current == nil {
+		return errors.New("task not found")
+	}
+	// Like tags, a parent or recurrence left out of the request is kept;
+	// "" clears it, as does null through Patch.
+	if task.ParentID == nil {
+		task.ParentID = current.ParentID
 	}
+	if task.Recurrence == nil {
+		task.Recurrence = current.Recurrence
+	}
+	return s.update(ctx, current, task, categoryName, opts, false)
+}
 
-	ok, err = s.StatusPrioritiesRepository.PrioritiesExist(ctx, task.PriorityID, task.UserID)
+// update takes an existing task to its new state. A partial update writes
+// only the columns that changed, so an edit never clobbers fields the
+// caller did not send.
+func (s *taskService) update(ctx context.Context, current, task *model.Task, categoryName string, opts TaskUpdateOptions, partial bool) error {
+	if err := checkRevision(current, opts.IfMatch); err != nil {
+		return err
+	}
+	task.Revision = current.Revision
+
+	status, err := s.validateTask(ctx, task, current, categoryName)
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return errors.New("invalid priority ID")
+	if err := normalizeRecurrence(task); err != nil {
+		return err
+	}
+	if err := applyRecurrenceScope(task, current, opts.Scope); err != nil {
+		return err
 	}
 
-	if task.CategoryID != nil && *task.CategoryID != "" {
-		owned, err := s.CategoryRepository.ExistOwned(ctx, *task.CategoryID, task.UserID)
+	if task.StatusID != current.StatusID {
+		transitions, err := s.WorkflowRepository.ListTransitions(ctx, task.UserID)
 		if err != nil {
 			return err
 		}
-		if !owned {
-			return errors.New("category does not belong to user")
+		if err := checkTransition(transitions, current.StatusID, task.StatusID, opts.StatusComment); err != nil {
+			return err
+		}
+	}
+
+	if status.IsTerminal && current.CompletedAt == nil && !opts.Force {
+		blockers, err := s.DependencyRepository.OpenBlockers(ctx, task.ID, task.UserID)
+		if err != nil {
+			return err
+		}
+		if len(blockers) > 0 {
+			return &TransitionError{
+				Message:      "task is blocked by unfinished tasks",
+				Code:         "task_blocked",
+				FromStatusID: current.StatusID,
+				ToStatusID:   task.StatusID,
+				BlockedBy:    blockers,
+			}
 		}
 	}
 
-	return s.TaskRepository.Update(ctx, task)
+	switch {
+	case !status.IsTerminal:
+		task.CompletedAt = nil
+	case current.CompletedAt != nil:
+		task.CompletedAt = current.CompletedAt
+	default:
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+
+	return s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if !partial {
+			err = s.TaskRepository.Update(ctx, task)
+		} else if columns := repository.ChangedTaskColumns(current, task); len(columns) > 0 || task.Tags != nil {
+			err = s.TaskRepository.Patch(ctx, task, columns)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// The row existed a moment ago, so someone else wrote it first.
+			return ErrTaskModified
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.saveTags(ctx, task); err != nil {
+			return err
+		}
+		if task.Tags == nil {
+			task.Tags = current.Tags
+		}
+		if err := s.recordEvent(ctx, model.TaskEventUpdated, task.UserID, current, task); err != nil {
+			return err
+		}
+		if err := s.saveVersion(ctx, task.UserID, current, task, opts.revertedFrom); err != nil {
+			return err
+		}
+		if !dueEqual(current, task) {
+			if err := s.rescheduleReminders(ctx, task); err != nil {
+				return err
+			}
+		}
+
+		if task.StatusID != current.StatusID {
+			err := s.WorkflowRepository.RecordStatusChange(ctx, task.ID, task.UserID, current.StatusID, task.StatusID, strings.TrimSpace(opts.StatusComment))
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.Scope == ScopeFuture {
+			if err := s.updateSeries(ctx, task); err != nil {
+				return err
+			}
+		}
+
+		if status.IsTerminal && current.CompletedAt == nil {
+			return s.spawnNextOccurrence(ctx, task)
+		}
+		return nil
+	})
+}
+
+// checkRevision enforces an If-Match precondition against the stored task.
+func checkRevision(current *model.Task, ifMatch []int) error {
+	if ifMatch == nil {
+		return nil
+	}
+	for _, rev := range ifMatch {
+		if rev == current.Revision {
+			return nil
+		}
+	}
+	return ErrTaskModified
 }
 
-func (s *taskService) Delete(ctx context.Context, taskID, userID string) error {
+func (s *taskService) Delete(ctx context.Context, taskID, userID string, mode repository.ChildDeleteMode, ifMatch []int) error {
 	if userID == "" || taskID == "" {
 		return errors.New("userID and taskID required")
 	}
-	return s.TaskRepository.Delete(ctx, taskID, userID)
+	switch mode {
+	case "":
+		mode = repository.DeleteChildrenCascade
+	case repository.DeleteChildrenCascade, repository.DeleteChildrenReparent:
+	default:
+		return ErrInvalidChildDeleteMode
+	}
+
+	return s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return sql.ErrNoRows
+		}
+		if err := checkRevision(current, ifMatch); err != nil {
+			return err
+		}
+		// Cascades take the whole subtree; reparenting only touches direct children.
+		depth := 0
+		if mode == repository.DeleteChildrenReparent {
+			depth = 1
+		}
+		affected, err := s.TaskRepository.ListDescendants(ctx, taskID, userID, depth)
+		if err != nil {
+			return err
+		}
+
+		if err := s.TaskRepository.Delete(ctx, taskID, userID, mode); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, model.TaskEventDeleted, userID, current, nil); err != nil {
+			return err
+		}
+		for _, n := range affected {
+			before := n.Task
+			if mode == repository.DeleteChildrenCascade {
+				err = s.recordEvent(ctx, model.TaskEventDeleted, userID, &before, nil)
+			} else {
+				after := n.Task
+				after.ParentID = current.ParentID
+				err = s.recordEvent(ctx, model.TaskEventUpdated, userID, &before, &after)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+type fakeTaskRepo struct {
+	repository.TaskRepository
+	tasks map[string]*model.Task
+}
+
+func (r *fakeTaskRepo) GetByID(ctx context.Context, taskID, userID string) (*model.Task, error) {
+	t, ok := r.tasks[taskID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (r *fakeTaskRepo) IsDescendant(ctx context.Context, ancestorID, taskID, userID string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, t *model.Task) error {
+	t.Revision++
+	cp := *t
+	r.tasks[t.ID] = &cp
+	return nil
+}
+
+type fakeStatusRepo struct {
+	repository.StatusPrioritiesRepository
+}
+
+func (fakeStatusRepo) GetStatus(ctx context.Context, statusID int, userID string) (*model.Status, error) {
+	return &model.Status{ID: statusID}, nil
+}
+
+func (fakeStatusRepo) PrioritiesExist(ctx context.Context, priorityID int, userID string) (bool, error) {
+	return true, nil
+}
+
+type fakeEventRepo struct {
+	repository.TaskEventRepository
+}
+
+func (fakeEventRepo) Record(ctx context.Context, e *model.TaskEvent) error { return nil }
+
+type fakeVersionRepo struct {
+	repository.TaskVersionRepository
+}
+
+func (fakeVersionRepo) Latest(ctx context.Context, taskID string) (int, error) { return 1, nil }
+
+func (fakeVersionRepo) Append(ctx context.Context, v *model.TaskVersion) error { return nil }
+
+type fakeWorkflowRepo struct {
+	repository.WorkflowRepository
+}
+
+func (fakeWorkflowRepo) ListTransitions(ctx context.Context, userID string) ([]*model.StatusTransition, error) {
+	return nil, nil
+}
+
+func (fakeWorkflowRepo) RecordStatusChange(ctx context.Context, taskID, userID string, from, to int, comment string) error {
+	return nil
+}
+
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }
+
+func (noTx) WithinSavepoint(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func strPtr(s string) *string { return &s }
+
+func newUpdateTestService() (*taskService, *fakeTaskRepo) {
+	repo := &fakeTaskRepo{tasks: map[string]*model.Task{
+		"parent": {ID: "parent", UserID: "user", Title: "Parent", StatusID: 1, PriorityID: 1},
+		"child": {
+			ID: "child", UserID: "user", ParentID: strPtr("parent"),
+			Title: "Child", StatusID: 1, PriorityID: 1, Tags: []string{},
+		},
+	}}
+	return &taskService{
+		TaskRepository:             repo,
+		StatusPrioritiesRepository: fakeStatusRepo{},
+		WorkflowRepository:         fakeWorkflowRepo{},
+		TaskEventRepository:        fakeEventRepo{},
+		TaskVersionRepository:      fakeVersionRepo{},
+		Transactor:                 noTx{},
+	}, repo
+}
+
+func TestUpdateKeepsOmittedParent(t *testing.T) {
+	svc, repo := newUpdateTestService()
+
+	// The status toggle in the UI sends neither parent_id nor recurrence.
+	task := &model.Task{ID: "child", UserID: "user", Title: "Child", StatusID: 2, PriorityID: 1}
+	if err := svc.Update(context.Background(), task, "", TaskUpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.tasks["child"].ParentID; got == nil || *got != "parent" {
+		t.Errorf("parent after a PUT without parent_id = %v, want parent", got)
+	}
+}
+
+func TestUpdateClearsParent(t *testing.T) {
+	svc, repo := newUpdateTestService()
+
+	task := &model.Task{ID: "child", UserID: "user", ParentID: strPtr(""), Title: "Child", StatusID: 1, PriorityID: 1}
+	if err := svc.Update(context.Background(), task, "", TaskUpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.tasks["child"].ParentID; got != nil {
+		t.Errorf("parent after a PUT with an empty parent_id = %q, want none", *got)
+	}
+}
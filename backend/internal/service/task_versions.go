@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+var ErrTaskVersionNotFound = errors.New("task version not found")
+
+func (s *taskService) saveVersion(ctx context.Context, userID string, before, after *model.Task, revertedFrom *int) error {
+	return saveVersion(ctx, s.TaskVersionRepository, userID, before, after, revertedFrom)
+}
+
+// saveVersion appends the state after a write to the task's version list.
+// Tasks written before versions existed get their prior state stored first,
+// so that it can be reverted to.
+func saveVersion(ctx context.Context, versions repository.TaskVersionRepository, userID string, before, after *model.Task, revertedFrom *int) error {
+	if before != nil && revertedFrom == nil && len(diffTask(before, after)) == 0 {
+		return nil
+	}
+
+	if before != nil {
+		latest, err := versions.Latest(ctx, after.ID)
+		if err != nil {
+			return err
+		}
+		if latest == 0 {
+			if err := appendVersion(ctx, versions, userID, before, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return appendVersion(ctx, versions, userID, after, revertedFrom)
+}
+
+func appendVersion(ctx context.Context, versions repository.TaskVersionRepository, userID string, t *model.Task, revertedFrom *int) error {
+	snapshot, err := json.Marshal(snapshotOf(t))
+	if err != nil {
+		return err
+	}
+	return versions.Append(ctx, &model.TaskVersion{
+		TaskID:       t.ID,
+		UserID:       userID,
+		Snapshot:     snapshot,
+		RevertedFrom: revertedFrom,
+	})
+}
+
+func (s *taskService) Versions(ctx context.Context, taskID, userID string) ([]*model.TaskVersion, error) {
+	task, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return s.TaskVersionRepository.ListByTask(ctx, taskID, userID)
+}
+
+// checkSnapshotRefs reports references in a stored version that no longer
+// resolve, so a revert fails with a clearer message than Update would give.
+// Like Update it accepts the task's current category even while that
+// category is in the trash.
+func (s *taskService) checkSnapshotRefs(ctx context.Context, current *model.Task, version int, snap auditFields) error {
+	userID := current.UserID
+	status, err := s.StatusPrioritiesRepository.GetStatus(ctx, snap.StatusID, userID)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		return fmt.Errorf("the status of version %d no longer exists", version)
+	}
+
+	ok, err := s.StatusPrioritiesRepository.PrioritiesExist(ctx, snap.PriorityID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the priority of version %d no longer exists", version)
+	}
+
+	if snap.CategoryID != nil && !sameCategory(snap.CategoryID, current.CategoryID) {
+		ok, err := s.CategoryRepository.ExistOwned(ctx, *snap.CategoryID, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("the category of version %d no longer exists", version)
+		}
+	}
+
+	if snap.ParentID != nil {
+		parent, err := s.TaskRepository.GetByID(ctx, *snap.ParentID, userID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return fmt.Errorf("the parent task of version %d no longer exists", version)
+		}
+	}
+	return nil
+}
+
+// Revert restores the fields stored in a version through the regular Update
+// path, so workflow rules and validation apply and the revert itself becomes
+// a new version. statusComment is passed on to a status change that needs one.
+func (s *taskService) Revert(ctx context.Context, taskID, userID string, version int, statusComment string) (*model.Task, error) {
+	current, err := s.TaskRepository.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	v, err := s.TaskVersionRepository.Get(ctx, taskID, userID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrTaskVersionNotFound
+	}
+
+	var snap auditFields
+	if err := json.Unmarshal(v.Snapshot, &snap); err != nil {
+		return nil, err
+	}
+	if err := s.checkSnapshotRefs(ctx, current, version, snap); err != nil {
+		return nil, err
+	}
+
+	task := &model.Task{
+		ID:          taskID,
+		UserID:      userID,
+		ParentID:    snap.ParentID,
+		CategoryID:  snap.CategoryID,
+		StatusID:    snap.StatusID,
+		PriorityID:  snap.PriorityID,
+		Title:       snap.Title,
+		Description: snap.Description,
+		DueDate:     snap.DueDate,
+		DueAllDay:   snap.DueDate != nil && (snap.DueAllDay == nil || *snap.DueAllDay),
+		Recurrence:  snap.Recurrence,
+		Tags:        append([]string{}, snap.Tags...),
+	}
+
+	opts := TaskUpdateOptions{
+		StatusComment: statusComment,
+		Scope:         ScopeThis,
+		revertedFrom:  &version,
+	}
+	if current.Recurrence != nil && !sameRecurrence(task.Recurrence, current.Recurrence) {
+		opts.Scope = ScopeFuture
+	}
+	// Update keeps a parent or recurrence that is left out, so ask for none
+	// explicitly.
+	if task.ParentID == nil {
+		task.ParentID = new(string)
+	}
+	if task.Recurrence == nil {
+		task.Recurrence = new(string)
+	}
+
+	if err := s.Update(ctx, task, "", opts); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, taskID, userID)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+var (
+	ErrInvalidTimezone = errors.New("timezone must be an IANA name such as Europe/Berlin")
+	ErrInvalidDueDate  = errors.New("due_date must be YYYY-MM-DD or an RFC 3339 date-time")
+)
+
+// LoadTimezone resolves an IANA zone name. "Local" is refused: it would mean
+// the server's zone, not the user's.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// ParseDueDate reads a due date. A date-only YYYY-MM-DD value is an all-day
+// date at midnight UTC; an RFC 3339 date-time is a point in time.
+func ParseDueDate(s string) (due time.Time, allDay bool, err error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, ErrInvalidDueDate
+	}
+	return t.UTC(), false, nil
+}
+
+// ParseDueDateAs is ParseDueDate with the all-day flag given rather than
+// inferred from the format, so a stored due date can be sent back as it was
+// read. An all-day date keeps the UTC calendar day of the value. A nil
+// allDay infers it.
+func ParseDueDateAs(s string, allDay *bool) (time.Time, bool, error) {
+	due, inferred, err := ParseDueDate(s)
+	if err != nil || allDay == nil {
+		return due, inferred, err
+	}
+	if *allDay {
+		due = asAllDay(due)
+	}
+	return due, *allDay, nil
+}
+
+// asAllDay moves t to midnight UTC of its UTC calendar day, where all-day
+// due dates are stored.
+func asAllDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// localToday is the current date in loc, at midnight UTC like all-day due
+// dates.
+func localToday(loc *time.Location) time.Time {
+	y, m, d := time.Now().In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// userLocation loads the user's timezone preference, falling back to UTC.
+func userLocation(ctx context.Context, users repository.UserRepository, userID string) (*time.Location, error) {
+	name, err := users.GetTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadTimezone(name)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// dueDay is the calendar day a task is due on as seen in loc.
+func dueDay(t *time.Time, allDay bool, loc *time.Location) time.Time {
+	if allDay {
+		return t.UTC()
+	}
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueDateAs(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		in         string
+		allDay     *bool
+		want       string
+		wantAllDay bool
+	}{
+		{"2025-01-15", nil, "2025-01-15T00:00:00Z", true},
+		{"2025-01-15T09:30:00+07:00", nil, "2025-01-15T02:30:00Z", false},
+		// A stored all-day date comes back as midnight UTC.
+		{"2025-01-15T00:00:00Z", &yes, "2025-01-15T00:00:00Z", true},
+		{"2025-01-15T09:30:00Z", &yes, "2025-01-15T00:00:00Z", true},
+		{"2025-01-15T09:30:00Z", &no, "2025-01-15T09:30:00Z", false},
+		{"2025-01-15", &no, "2025-01-15T00:00:00Z", false},
+	}
+	for _, tt := range tests {
+		got, allDay, err := ParseDueDateAs(tt.in, tt.allDay)
+		if err != nil {
+			t.Errorf("ParseDueDateAs(%q): %v", tt.in, err)
+			continue
+		}
+		if got.Format(time.RFC3339) != tt.want || allDay != tt.wantAllDay {
+			t.Errorf("ParseDueDateAs(%q, %v) = %s, %v; want %s, %v",
+				tt.in, tt.allDay != nil && *tt.allDay, got.Format(time.RFC3339), allDay, tt.want, tt.wantAllDay)
+		}
+	}
+
+	if _, _, err := ParseDueDateAs("15/01/2025", &yes); err != ErrInvalidDueDate {
+		t.Errorf("ParseDueDateAs of a bad date: err = %v, want ErrInvalidDueDate", err)
+	}
+}
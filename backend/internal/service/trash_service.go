@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+	"github.com/liaa-aa/task-manager-project/backend/internal/storage"
+)
+
+const (
+	TrashKindTask     = "task"
+	TrashKindCategory = "category"
+)
+
+var (
+	ErrInvalidTrashKind  = errors.New("kind must be task or category")
+	ErrTrashItemNotFound = errors.New("item not found in trash")
+	ErrTrashNameTaken    = errors.New("a category with this name already exists")
+)
+
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+type Trash struct {
+	Tasks         []*model.Task     `json:"tasks"`
+	Categories    []*model.Category `json:"categories"`
+	RetentionDays int               `json:"retention_days"`
+}
+
+type TrashService interface {
+	List(ctx context.Context, userID string) (*Trash, error)
+	Restore(ctx context.Context, kind, id, userID string) error
+	Purge(ctx context.Context, kind, id, userID string) error
+	Empty(ctx context.Context, userID string) error
+	// PurgeExpired permanently deletes everything trashed longer ago than the
+	// retention period, for all users.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type trashService struct {
+	TaskRepository       repository.TaskRepository
+	CategoryRepository   repository.CategoryRepository
+	AttachmentRepository repository.AttachmentRepository
+	TaskEventRepository  repository.TaskEventRepository
+	BlobStore            storage.BlobStore
+	Transactor           repository.Transactor
+	retention            time.Duration
+}
+
+func NewTrashService(
+	taskRepository repository.TaskRepository,
+	categoryRepository repository.CategoryRepository,
+	attachmentRepository repository.AttachmentRepository,
+	taskEventRepository repository.TaskEventRepository,
+	blobStore storage.BlobStore,
+	transactor repository.Transactor,
+) TrashService {
+	retention := defaultTrashRetention
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days > 0 {
+			retention = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return &trashService{
+		TaskRepository:       taskRepository,
+		CategoryRepository:   categoryRepository,
+		AttachmentRepository: attachmentRepository,
+		TaskEventRepository:  taskEventRepository,
+		BlobStore:            blobStore,
+		Transactor:           transactor,
+		retention:            retention,
+	}
+}
+
+func notInTrash(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTrashItemNotFound
+	}
+	return err
+}
+
+func (s *trashService) List(ctx context.Context, userID string) (*Trash, error) {
+	tasks, err := s.TaskRepository.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.CategoryRepository.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Trash{
+		Tasks:         tasks,
+		Categories:    categories,
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+	}, nil
+}
+
+func (s *trashService) Restore(ctx context.Context, kind, id, userID string) error {
+	switch kind {
+	case TrashKindTask:
+		return s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.TaskRepository.Restore(ctx, id, userID); err != nil {
+				return notInTrash(err)
+			}
+			return s.TaskEventRepository.Record(ctx, &model.TaskEvent{
+				TaskID:  id,
+				UserID:  userID,
+				Action:  model.TaskEventRestored,
+				Changes: map[string]model.FieldChange{},
+			})
+		})
+
+	case TrashKindCategory:
+		c, err := s.CategoryRepository.GetTrashed(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return ErrTrashItemNotFound
+		}
+		// Restore puts the category back at the top level if its parent is gone.
+		parentID := c.ParentID
+		if parentID != nil {
+			parent, err := s.CategoryRepository.GetByID(ctx, *parentID, userID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				parentID = nil
+			}
+		}
+		existing, err := s.CategoryRepository.GetByName(ctx, c.Name, userID, parentID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrTrashNameTaken
+		}
+		return notInTrash(s.CategoryRepository.Restore(ctx, id, userID))
+	}
+	return ErrInvalidTrashKind
+}
+
+func (s *trashService) Purge(ctx context.Context, kind, id, userID string) error {
+	switch kind {
+	case TrashKindTask:
+		var keys []string
+		err := s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			// Read the blob keys first: the attachment rows cascade away with the tasks.
+			keys, err = s.AttachmentRepository.StorageKeys(ctx, id, userID, true)
+			if err != nil {
+				return err
+			}
+			return notInTrash(s.TaskRepository.Purge(ctx, id, userID))
+		})
+		if err != nil {
+			return err
+		}
+		removeBlobs(ctx, s.BlobStore, keys)
+		return nil
+
+	case TrashKindCategory:
+		return notInTrash(s.CategoryRepository.Purge(ctx, id, userID))
+	}
+	return ErrInvalidTrashKind
+}
+
+// purgeBefore deletes tasks and categories trashed before the cutoff, then
+// removes the blobs of the purged tasks' attachments.
+func (s *trashService) purgeBefore(ctx context.Context, userID string, before time.Time) (int64, error) {
+	var keys []string
+	var purged int64
+	err := s.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		keys, err = s.AttachmentRepository.TrashedStorageKeys(ctx, userID, before)
+		if err != nil {
+			return err
+		}
+		tasks, err := s.TaskRepository.PurgeTrashed(ctx, userID, before)
+		if err != nil {
+			return err
+		}
+		categories, err := s.CategoryRepository.PurgeTrashed(ctx, userID, before)
+		if err != nil {
+			return err
+		}
+		purged = tasks + categories
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	removeBlobs(ctx, s.BlobStore, keys)
+	return purged, nil
+}
+
+func (s *trashService) Empty(ctx context.Context, userID string) error {
+	if userID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	_, err := s.purgeBefore(ctx, userID, time.Now())
+	return err
+}
+
+func (s *trashService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.purgeBefore(ctx, "", time.Now().Add(-s.retention))
+}
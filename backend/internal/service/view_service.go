@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+	"github.com/liaa-aa/task-manager-project/backend/internal/taskquery"
+)
+
+type ViewService interface {
+	List(ctx context.Context, userID string) ([]*model.SavedView, error)
+	Get(ctx context.Context, viewID, userID string) (*model.SavedView, error)
+	Create(ctx context.Context, view *model.SavedView) error
+	Update(ctx context.Context, view *model.SavedView) error
+	Delete(ctx context.Context, viewID, userID string) error
+	Tasks(ctx context.Context, viewID, userID string, cursor *repository.TaskCursor) (*ViewTasks, error)
+}
+
+// TaskGroup is one bucket of a grouped view, listing its tasks in the
+// order they appear in ViewTasks.Tasks.
+type TaskGroup struct {
+	Key     string   `json:"key"`
+	Label   string   `json:"label"`
+	TaskIDs []string `json:"task_ids"`
+}
+
+type ViewTasks struct {
+	View       *model.SavedView `json:"view"`
+	Tasks      []*model.Task    `json:"tasks"`
+	Groups     []*TaskGroup     `json:"groups,omitempty"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type viewService struct {
+	SavedViewRepository        repository.SavedViewRepository
+	TaskRepository             repository.TaskRepository
+	StatusPrioritiesRepository repository.StatusPrioritiesRepository
+	UserRepository             repository.UserRepository
+}
+
+func NewViewService(
+	savedViewRepository repository.SavedViewRepository,
+	taskRepository repository.TaskRepository,
+	statusPrioritiesRepository repository.StatusPrioritiesRepository,
+	userRepository repository.UserRepository,
+) ViewService {
+	return &viewService{
+		SavedViewRepository:        savedViewRepository,
+		TaskRepository:             taskRepository,
+		StatusPrioritiesRepository: statusPrioritiesRepository,
+		UserRepository:             userRepository,
+	}
+}
+
+var (
+	ErrViewNotFound = errors.New("view not found")
+	ErrInvalidView  = errors.New("invalid view")
+)
+
+const maxViewNameLength = 100
+
+var viewGroupings = map[string]bool{
+	"":         true,
+	"status":   true,
+	"priority": true,
+	"category": true,
+	"due_date": true,
+}
+
+// resolveViewDate reads a stored date, either YYYY-MM-DD or a token such as
+// "today+7d" counted in days, weeks or months from today.
+func resolveViewDate(value string, today time.Time) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	d, err := taskquery.ParseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	day := d.On(today)
+	return &day, nil
+}
+
+// viewFilter turns a stored query into a task filter as of today in loc.
+func viewFilter(q model.ViewQuery, loc *time.Location, cursor *repository.TaskCursor) (repository.TaskFilter, error) {
+	today := localToday(loc)
+	f := repository.TaskFilter{
+		StatusID:             q.StatusID,
+		PriorityID:           q.PriorityID,
+		CategoryID:           q.CategoryID,
+		IncludeSubcategories: q.IncludeSubcategories,
+		Overdue:              q.Overdue,
+		Completed:            q.Completed,
+		Blocked:              q.Blocked,
+		Archived:             q.Archived,
+		Tags:                 append([]string(nil), q.Tags...),
+		TagMode:              q.TagMode,
+		Search:               q.Search,
+		Sort:                 q.Sort,
+		Order:                q.Order,
+		Limit:                q.Limit,
+		Cursor:               cursor,
+		Location:             loc,
+		Today:                today,
+	}
+	var err error
+	if f.DueFrom, err = resolveViewDate(q.DueFrom, today); err != nil {
+		return f, fmt.Errorf("due_from: %w", err)
+	}
+	if f.DueTo, err = resolveViewDate(q.DueTo, today); err != nil {
+		return f, fmt.Errorf("due_to: %w", err)
+	}
+	if err := validateTaskFilter(&f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+func (s *viewService) validateView(ctx context.Context, view *model.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidView)
+	}
+	if len(view.Name) > maxViewNameLength {
+		return fmt.Errorf("%w: name is too long", ErrInvalidView)
+	}
+
+	view.Query.GroupBy = strings.ToLower(view.Query.GroupBy)
+	if !viewGroupings[view.Query.GroupBy] {
+		return fmt.Errorf("%w: group_by must be status, priority, category or due_date", ErrInvalidView)
+	}
+	loc, err := userLocation(ctx, s.UserRepository, view.UserID)
+	if err != nil {
+		return err
+	}
+	if _, err := viewFilter(view.Query, loc, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+
+	existing, err := s.SavedViewRepository.GetByName(ctx, view.Name, view.UserID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != view.ID {
+		return fmt.Errorf("%w: a view with this name already exists", ErrInvalidView)
+	}
+	return nil
+}
+
+func (s *viewService) List(ctx context.Context, userID string) ([]*model.SavedView, error) {
+	return s.SavedViewRepository.ListByUser(ctx, userID)
+}
+
+func (s *viewService) Get(ctx context.Context, viewID, userID string) (*model.SavedView, error) {
+	view, err := s.SavedViewRepository.GetByID(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return nil, ErrViewNotFound
+	}
+	return view, nil
+}
+
+func (s *viewService) Create(ctx context.Context, view *model.SavedView) error {
+	if err := s.validateView(ctx, view); err != nil {
+		return err
+	}
+	return s.SavedViewRepository.Create(ctx, view)
+}
+
+func (s *viewService) Update(ctx context.Context, view *model.SavedView) error {
+	if _, err := s.Get(ctx, view.ID, view.UserID); err != nil {
+		return err
+	}
+	if err := s.validateView(ctx, view); err != nil {
+		return err
+	}
+	return s.SavedViewRepository.Update(ctx, view)
+}
+
+func (s *viewService) Delete(ctx context.Context, viewID, userID string) error {
+	err := s.SavedViewRepository.Delete(ctx, viewID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrViewNotFound
+	}
+	return err
+}
+
+// Tasks runs a saved view, resolving its relative dates against today.
+func (s *viewService) Tasks(ctx context.Context, viewID, userID string, cursor *repository.TaskCursor) (*ViewTasks, error) {
+	view, err := s.Get(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userLocation(ctx, s.UserRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := viewFilter(view.Query, loc, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+
+	page, err := s.TaskRepository.ListByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ViewTasks{View: view, Tasks: page.Tasks, NextCursor: page.NextCursor}
+	if view.Query.GroupBy != "" {
+		if result.Groups, err = s.groupTasks(ctx, userID, view.Query.GroupBy, page.Tasks, loc); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// groupTasks buckets tasks by one field, keeping groups in the order their
+// first task appears. Timed due dates group by their day in loc.
+func (s *viewService) groupTasks(ctx context.Context, userID, groupBy string, tasks []*model.Task, loc *time.Location) ([]*TaskGroup, error) {
+	labels := map[string]string{}
+	switch groupBy {
+	case "status":
+		statuses, err := s.StatusPrioritiesRepository.ListStatuses(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range statuses {
+			labels[strconv.Itoa(st.ID)] = st.Name
+		}
+	case "priority":
+		priorities, err := s.StatusPrioritiesRepository.ListPriorities(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range priorities {
+			labels[strconv.Itoa(p.ID)] = p.Name
+		}
+	}
+
+	groups := []*TaskGroup{}
+	byKey := map[string]*TaskGroup{}
+	for _, t := range tasks {
+		var key, label string
+		switch groupBy {
+		case "status":
+			key = strconv.Itoa(t.StatusID)
+			label = labels[key]
+		case "priority":
+			key = strconv.Itoa(t.PriorityID)
+			label = labels[key]
+		case "category":
+			label = "Uncategorized"
+			if t.CategoryID != nil && t.CategoryName != nil {
+				key, label = *t.CategoryID, *t.CategoryName
+			}
+		case "due_date":
+			label = "No due date"
+			if t.DueDate != nil {
+				key = dueDay(t.DueDate, t.DueAllDay, loc).Format("2006-01-02")
+				label = key
+			}
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &TaskGroup{Key: key, Label: label, TaskIDs: []string{}}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.TaskIDs = append(g.TaskIDs, t.ID)
+	}
+	return groups, nil
+}
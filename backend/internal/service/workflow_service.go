@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liaa-aa/task-manager-project/backend/internal/model"
+	"github.com/liaa-aa/task-manager-project/backend/internal/repository"
+)
+
+// TransitionError is returned when a task status change breaks the user's
+// workflow. It is encoded as-is in the response body.
+type TransitionError struct {
+	Message      string   `json:"error"`
+	Code         string   `json:"code"`
+	FromStatusID int      `json:"from_status_id"`
+	ToStatusID   int      `json:"to_status_id"`
+	Allowed      []int    `json:"allowed_status_ids,omitempty"`
+	BlockedBy    []string `json:"blocked_by,omitempty"`
+}
+
+func (e *TransitionError) Error() string {
+	return e.Message
+}
+
+type Workflow struct {
+	Transitions       []*model.StatusTransition `json:"transitions"`
+	TerminalStatusIDs []int                     `json:"terminal_status_ids"`
+}
+
+type WorkflowService interface {
+	Get(ctx context.Context, userID string) (*Workflow, error)
+	AddTransition(ctx context.Context, t *model.StatusTransition) error
+	RemoveTransition(ctx context.Context, transitionID int, userID string) error
+}
+
+type workflowService struct {
+	WorkflowRepository         repository.WorkflowRepository
+	StatusPrioritiesRepository repository.StatusPrioritiesRepository
+}
+
+func NewWorkflowService(
+	workflowRepository repository.WorkflowRepository,
+	statusPrioritiesRepository repository.StatusPrioritiesRepository,
+) WorkflowService {
+	return &workflowService{
+		WorkflowRepository:         workflowRepository,
+		StatusPrioritiesRepository: statusPrioritiesRepository,
+	}
+}
+
+// checkTransition applies the user's transitions to a status change. A user
+// without any transitions has an open workflow where every move is allowed.
+func checkTransition(transitions []*model.StatusTransition, from, to int, comment string) error {
+	if from == to || len(transitions) == 0 {
+		return nil
+	}
+
+	allowed := []int{}
+	var match *model.StatusTransition
+	for _, t := range transitions {
+		if t.FromStatusID != from {
+			continue
+		}
+		allowed = append(allowed, t.ToStatusID)
+		if t.ToStatusID == to {
+			match = t
+		}
+	}
+
+	if match == nil {
+		return &TransitionError{
+			Message:      fmt.Sprintf("transition from status %d to %d is not allowed", from, to),
+			Code:         "transition_not_allowed",
+			FromStatusID: from,
+			ToStatusID:   to,
+			Allowed:      allowed,
+		}
+	}
+	if match.RequiresComment && strings.TrimSpace(comment) == "" {
+		return &TransitionError{
+			Message:      fmt.Sprintf("transition from status %d to %d requires a comment", from, to),
+			Code:         "comment_required",
+			FromStatusID: from,
+			ToStatusID:   to,
+			Allowed:      allowed,
+		}
+	}
+	return nil
+}
+
+func (s *workflowService) Get(ctx context.Context, userID string) (*Workflow, error) {
+	transitions, err := s.WorkflowRepository.ListTransitions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.StatusPrioritiesRepository.ListStatuses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wf := &Workflow{Transitions: transitions, TerminalStatusIDs: []int{}}
+	for _, st := range statuses {
+		if st.IsTerminal {
+			wf.TerminalStatusIDs = append(wf.TerminalStatusIDs, st.ID)
+		}
+	}
+	return wf, nil
+}
+
+func (s *workflowService) AddTransition(ctx context.Context, t *model.StatusTransition) error {
+	if t.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if t.FromStatusID == t.ToStatusID {
+		return errors.New("a transition needs two different statuses")
+	}
+
+	for _, id := range []int{t.FromStatusID, t.ToStatusID} {
+		ok, err := s.StatusPrioritiesRepository.StatusExist(ctx, id, t.UserID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("invalid status ID")
+		}
+	}
+
+	return s.WorkflowRepository.CreateTransition(ctx, t)
+}
+
+func (s *workflowService) RemoveTransition(ctx context.Context, transitionID int, userID string) error {
+	err := s.WorkflowRepository.DeleteTransition(ctx, transitionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("transition not found")
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+package taskquery
+
+import (
+	"strings"
+)
+
+// Op is the comparison of a field term. "!=" is not an Op of its own: it
+// parses as a negated OpEq.
+type Op string
+
+const (
+	OpEq Op = ":"
+	OpLt Op = "<"
+	OpLe Op = "<="
+	OpGt Op = ">"
+	OpGe Op = ">="
+)
+
+// Node carries what every term has: its 1-based position in the input and
+// whether it was negated with a leading "-" or with "!=".
+type Node struct {
+	Pos     int
+	Negated bool
+}
+
+func (n Node) base() Node { return n }
+
+// Term is one of the term types below.
+type Term interface {
+	base() Node
+	String() string
+}
+
+// Query is a parsed query string. Its terms are ANDed together.
+type Query struct {
+	Terms []Term
+}
+
+// StatusTerm matches a status by name. "open" also matches any
+// non-terminal status and "done" or "closed" any terminal one.
+type StatusTerm struct {
+	Node
+	Name string
+}
+
+// PriorityTerm compares a task's priority with the named one by position.
+type PriorityTerm struct {
+	Node
+	Op   Op
+	Name string
+}
+
+// DateTerm compares the due or creation date. None matches tasks without
+// a due date.
+type DateTerm struct {
+	Node
+	Field string
+	Op    Op
+	Date  Date
+	None  bool
+}
+
+// CategoryTerm matches the category name. None matches uncategorised tasks.
+type CategoryTerm struct {
+	Node
+	Name string
+	None bool
+}
+
+type TagTerm struct {
+	Node
+	Name string
+}
+
+// IsTerm matches a task state: open, done, overdue, blocked, archived or
+// recurring.
+type IsTerm struct {
+	Node
+	State string
+}
+
+// TitleTerm matches text in the title only.
+type TitleTerm struct {
+	Node
+	Text string
+}
+
+// TextTerm matches text in the title or description. Phrase is set when
+// the text was quoted.
+type TextTerm struct {
+	Node
+	Text   string
+	Phrase bool
+}
+
+// Base returns the position and negation of any term.
+func Base(t Term) Node { return t.base() }
+
+// Has reports whether q contains a non-negated is:<state> term.
+func (q *Query) Has(state string) bool {
+	for _, t := range q.Terms {
+		if is, ok := t.(*IsTerm); ok && !is.Negated && is.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders q in canonical form; parsing the result gives back an
+// equivalent query.
+func (q *Query) String() string {
+	parts := make([]string, len(q.Terms))
+	for i, t := range q.Terms {
+		parts[i] = t.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// value renders a field value, quoting it when a bare word would not
+// survive a round trip: when it is empty, holds a space or quote, or
+// starts with a character that would read as part of the operator.
+func value(s string) string {
+	if s == "" || strings.ContainsAny(s, `"`) || strings.ContainsFunc(s, isSpace) || strings.ContainsAny(s[:1], "=<>") {
+		return quote(s)
+	}
+	return s
+}
+
+func field(n Node, name string, op Op, v string) string {
+	if n.Negated && op == OpEq {
+		return name + "!=" + v
+	}
+	s := name + string(op) + v
+	if n.Negated {
+		s = "-" + s
+	}
+	return s
+}
+
+func (t *StatusTerm) String() string { return field(t.Node, "status", OpEq, value(t.Name)) }
+
+func (t *PriorityTerm) String() string { return field(t.Node, "priority", t.Op, value(t.Name)) }
+
+func (t *DateTerm) String() string {
+	if t.None {
+		return field(t.Node, t.Field, OpEq, "none")
+	}
+	return field(t.Node, t.Field, t.Op, t.Date.String())
+}
+
+func (t *CategoryTerm) String() string {
+	if t.None {
+		return field(t.Node, "cat", OpEq, "none")
+	}
+	return field(t.Node, "cat", OpEq, value(t.Name))
+}
+
+func (t *TagTerm) String() string { return field(t.Node, "tag", OpEq, value(t.Name)) }
+
+func (t *IsTerm) String() string { return field(t.Node, "is", OpEq, t.State) }
+
+func (t *TitleTerm) String() string { return field(t.Node, "title", OpEq, value(t.Text)) }
+
+func (t *TextTerm) String() string {
+	s := t.Text
+	if t.Phrase {
+		s = quote(s)
+	}
+	if t.Negated {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package taskquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date is either an absolute day or a day relative to today, such as
+// "today+7d". Relative dates are resolved with On each time a query runs.
+type Date struct {
+	Relative bool
+	// Offset and Unit ('d', 'w' or 'm') apply to relative dates.
+	Offset int
+	Unit   byte
+	// Abs is the day of an absolute date, at midnight UTC.
+	Abs time.Time
+}
+
+var relativeDatePattern = regexp.MustCompile(`^today(?:([+-])(\d{1,4})([dwm]))?$`)
+
+// ParseDate reads YYYY-MM-DD or today[+-]N(d|w|m).
+func ParseDate(s string) (Date, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if m := relativeDatePattern.FindStringSubmatch(s); m != nil {
+		d := Date{Relative: true, Unit: 'd'}
+		if m[1] != "" {
+			d.Offset, _ = strconv.Atoi(m[2])
+			if m[1] == "-" {
+				d.Offset = -d.Offset
+			}
+			d.Unit = m[3][0]
+		}
+		return d, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, fmt.Errorf("%q is neither YYYY-MM-DD nor a relative date like today+7d", s)
+	}
+	return Date{Abs: t}, nil
+}
+
+// On resolves the date against today, which should be a midnight.
+func (d Date) On(today time.Time) time.Time {
+	if !d.Relative {
+		return d.Abs
+	}
+	switch d.Unit {
+	case 'w':
+		return today.AddDate(0, 0, 7*d.Offset)
+	case 'm':
+		// Clamp to the month's last day: Jan 31 + 1m is Feb 28, not Mar 3.
+		first := time.Date(today.Year(), today.Month()+time.Month(d.Offset), 1, 0, 0, 0, 0, today.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(today.Day(), last)-1)
+	default:
+		return today.AddDate(0, 0, d.Offset)
+	}
+}
+
+func (d Date) String() string {
+	switch {
+	case !d.Relative:
+		return d.Abs.Format("2006-01-02")
+	case d.Offset == 0:
+		return "today"
+	case d.Offset > 0:
+		return fmt.Sprintf("today+%d%c", d.Offset, d.Unit)
+	default:
+		return fmt.Sprintf("today%d%c", d.Offset, d.Unit)
+	}
+}
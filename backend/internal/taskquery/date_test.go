@@ -0,0 +1,78 @@
+package taskquery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Date
+	}{
+		{"today", Date{Relative: true, Unit: 'd'}},
+		{" TODAY ", Date{Relative: true, Unit: 'd'}},
+		{"today+7d", Date{Relative: true, Offset: 7, Unit: 'd'}},
+		{"today-1d", Date{Relative: true, Offset: -1, Unit: 'd'}},
+		{"today+2w", Date{Relative: true, Offset: 2, Unit: 'w'}},
+		{"today-12m", Date{Relative: true, Offset: -12, Unit: 'm'}},
+		{"2026-02-28", abs("2026-02-28")},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.input)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDate(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "tomorrow", "today+", "today+7", "today+7y", "today+12345d", "2026-02-30", "26-02-01"} {
+		if _, err := ParseDate(bad); err == nil {
+			t.Errorf("ParseDate(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestDateOn(t *testing.T) {
+	// Jan 31 tests the month clamping.
+	today := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"today", "2025-01-31"},
+		{"today+1d", "2025-02-01"},
+		{"today-31d", "2024-12-31"},
+		{"today+1w", "2025-02-07"},
+		{"today-1w", "2025-01-24"},
+		{"today+1m", "2025-02-28"},
+		{"today+13m", "2026-02-28"},
+		{"today-2m", "2024-11-30"},
+		{"today+3m", "2025-04-30"},
+		{"2020-02-29", "2020-02-29"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDate(tt.date)
+		if err != nil {
+			t.Fatalf("ParseDate(%q): %v", tt.date, err)
+		}
+		if got := d.On(today).Format("2006-01-02"); got != tt.want {
+			t.Errorf("%s on %s = %s, want %s", tt.date, today.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestDateString(t *testing.T) {
+	for _, s := range []string{"today", "today+7d", "today-2w", "today+1m", "2026-11-01"} {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.String(); got != s {
+			t.Errorf("ParseDate(%q).String() = %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package taskquery parses the compact task query language accepted by
+// GET /task?q=, for example:
+//
+//	status:open priority>=high due<2026-11-01 #work -cat:personal "release notes"
+//
+// Grammar (terms are separated by whitespace and ANDed):
+//
+//	query  = { term } .
+//	term   = [ "-" ] ( field op value | "#" word | phrase | word ) .
+//	field  = "status" | "priority" | "due" | "created" | "cat" | "category"
+//	       | "tag" | "is" | "title" .
+//	op     = ":" | "=" | "!=" | "<" | "<=" | ">" | ">=" .
+//	value  = word | phrase .
+//	phrase = `"` { any character, with \" and \\ escaped } `"` .
+//	word   = a run of characters other than whitespace and `"` .
+//
+// Field names are case-insensitive. ":" and "=" mean the same; "!=" is a
+// negated ":". Only priority, due and created accept ordering operators.
+// Dates are YYYY-MM-DD or relative to today, such as today+7d.
+package taskquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxLength = 1000
+	MaxTerms  = 50
+)
+
+// Error reports a problem at a 1-based character position of the input.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+var isStates = map[string]bool{
+	"open":      true,
+	"done":      true,
+	"overdue":   true,
+	"blocked":   true,
+	"archived":  true,
+	"recurring": true,
+}
+
+var fieldAliases = map[string]string{
+	"status":   "status",
+	"priority": "priority",
+	"due":      "due",
+	"created":  "created",
+	"cat":      "cat",
+	"category": "cat",
+	"tag":      "tag",
+	"is":       "is",
+	"title":    "title",
+}
+
+// orderedFields accept <, <=, > and >= besides equality.
+var orderedFields = map[string]bool{
+	"priority": true,
+	"due":      true,
+	"created":  true,
+}
+
+func isSpace(r rune) bool { return unicode.IsSpace(r) }
+
+func isFieldRune(r rune) bool { return r == '_' || (r < unicode.MaxASCII && unicode.IsLetter(r)) }
+
+type parser struct {
+	in  []rune
+	pos int // index into in; error positions are pos+1
+}
+
+func (p *parser) errorf(at int, format string, args ...any) *Error {
+	return &Error{Pos: at + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.in) }
+
+func (p *parser) skipSpace() {
+	for !p.eof() && isSpace(p.in[p.pos]) {
+		p.pos++
+	}
+}
+
+// readBare reads up to the next whitespace or quote.
+func (p *parser) readBare() string {
+	start := p.pos
+	for !p.eof() && !isSpace(p.in[p.pos]) && p.in[p.pos] != '"' {
+		p.pos++
+	}
+	return string(p.in[start:p.pos])
+}
+
+// readQuoted reads a phrase starting at the opening quote.
+func (p *parser) readQuoted() (string, error) {
+	open := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		r := p.in[p.pos]
+		switch {
+		case r == '"':
+			p.pos++
+			return b.String(), nil
+		case r == '\\' && p.pos+1 < len(p.in) && (p.in[p.pos+1] == '"' || p.in[p.pos+1] == '\\'):
+			b.WriteRune(p.in[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteRune(r)
+			p.pos++
+		}
+	}
+	return "", p.errorf(open, "unterminated quote")
+}
+
+// readOp reads an operator at the current position, if there is one.
+func (p *parser) readOp() (op Op, negated, ok bool) {
+	rest := p.in[p.pos:]
+	two := ""
+	if len(rest) >= 2 {
+		two = string(rest[:2])
+	}
+	switch two {
+	case "!=":
+		p.pos += 2
+		return OpEq, true, true
+	case "<=", ">=":
+		p.pos += 2
+		return Op(two), false, true
+	}
+	if len(rest) == 0 {
+		return "", false, false
+	}
+	switch rest[0] {
+	case ':', '=':
+		p.pos++
+		return OpEq, false, true
+	case '<', '>':
+		p.pos++
+		return Op(string(rest[0])), false, true
+	}
+	return "", false, false
+}
+
+// Parse parses a query string. Errors are *Error values carrying the
+// position of the offending term.
+func Parse(input string) (*Query, error) {
+	p := &parser{in: []rune(input)}
+	if len(p.in) > MaxLength {
+		return nil, p.errorf(MaxLength, "query is longer than %d characters", MaxLength)
+	}
+
+	q := &Query{}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		if len(q.Terms) == MaxTerms {
+			return nil, p.errorf(p.pos, "query has more than %d terms", MaxTerms)
+		}
+		t, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		q.Terms = append(q.Terms, t)
+	}
+}
+
+func (p *parser) parseTerm() (Term, error) {
+	node := Node{Pos: p.pos + 1}
+	if p.in[p.pos] == '-' {
+		p.pos++
+		if p.eof() || isSpace(p.in[p.pos]) {
+			return nil, p.errorf(node.Pos-1, "expected a term after -")
+		}
+		node.Negated = true
+	}
+
+	switch p.in[p.pos] {
+	case '"':
+		text, err := p.readQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return &TextTerm{Node: node, Text: text, Phrase: true}, nil
+	case '#':
+		at := p.pos
+		p.pos++
+		name := p.readBare()
+		if name == "" {
+			return nil, p.errorf(at, "expected a tag name after #")
+		}
+		return &TagTerm{Node: node, Name: name}, nil
+	}
+
+	start := p.pos
+	for !p.eof() && isFieldRune(p.in[p.pos]) {
+		p.pos++
+	}
+	if p.pos > start {
+		name := string(p.in[start:p.pos])
+		if op, negated, ok := p.readOp(); ok {
+			return p.parseField(node, start, name, op, negated)
+		}
+	}
+
+	p.pos = start
+	return &TextTerm{Node: node, Text: p.readBare()}, nil
+}
+
+func (p *parser) parseField(node Node, at int, name string, op Op, negated bool) (Term, error) {
+	field, ok := fieldAliases[strings.ToLower(name)]
+	if !ok {
+		return nil, p.errorf(at, "unknown field %q", name)
+	}
+	if negated {
+		if node.Negated {
+			return nil, p.errorf(at, "cannot combine - with !=")
+		}
+		node.Negated = true
+	}
+	if op != OpEq && !orderedFields[field] {
+		return nil, p.errorf(at, "%s only supports :, = and !=", field)
+	}
+
+	valueAt := p.pos
+	var v string
+	if !p.eof() && p.in[p.pos] == '"' {
+		var err error
+		if v, err = p.readQuoted(); err != nil {
+			return nil, err
+		}
+	} else {
+		v = p.readBare()
+	}
+	if strings.TrimSpace(v) == "" {
+		return nil, p.errorf(valueAt, "missing value for %s", field)
+	}
+
+	switch field {
+	case "status":
+		return &StatusTerm{Node: node, Name: v}, nil
+	case "priority":
+		return &PriorityTerm{Node: node, Op: op, Name: v}, nil
+	case "due", "created":
+		if strings.EqualFold(v, "none") {
+			if field != "due" || op != OpEq {
+				return nil, p.errorf(valueAt, "none is only valid as due:none")
+			}
+			return &DateTerm{Node: node, Field: field, Op: op, None: true}, nil
+		}
+		d, err := ParseDate(v)
+		if err != nil {
+			return nil, p.errorf(valueAt, "%v", err)
+		}
+		return &DateTerm{Node: node, Field: field, Op: op, Date: d}, nil
+	case "cat":
+		if strings.EqualFold(v, "none") {
+			return &CategoryTerm{Node: node, None: true}, nil
+		}
+		return &CategoryTerm{Node: node, Name: v}, nil
+	case "tag":
+		return &TagTerm{Node: node, Name: v}, nil
+	case "is":
+		state := strings.ToLower(v)
+		if !isStates[state] {
+			return nil, p.errorf(valueAt, "is must be open, done, overdue, blocked, archived or recurring")
+		}
+		return &IsTerm{Node: node, State: state}, nil
+	default:
+		return &TitleTerm{Node: node, Text: v}, nil
+	}
+}
@@ -0,0 +1,53 @@
+package taskquery
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		``,
+		`status:open priority>=high due<2026-11-01 #work -cat:personal "release notes"`,
+		`is:overdue -is:done due<=today+7d created>today-2w`,
+		`priority!=low cat:none due:none title:"weekly \"sync\""`,
+		`tag:"two words" -"not this" --x a:b`,
+		`"unterminated`,
+		`- status:`,
+		`due<2026-13-40 is:sleeping`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		q, err := Parse(input)
+		if err != nil {
+			var perr *Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("Parse(%q) returned %T, want *Error", input, err)
+			}
+			if perr.Pos < 1 || perr.Pos > utf8.RuneCountInString(input)+1 {
+				t.Fatalf("Parse(%q) error position %d is outside the input", input, perr.Pos)
+			}
+			return
+		}
+		if len(q.Terms) > MaxTerms {
+			t.Fatalf("Parse(%q) returned %d terms, limit is %d", input, len(q.Terms), MaxTerms)
+		}
+
+		// The canonical form must parse back to itself. It can outgrow the
+		// length limit by adding separators, so skip those.
+		canonical := q.String()
+		if utf8.RuneCountInString(canonical) > MaxLength {
+			return
+		}
+		again, err := Parse(canonical)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed on canonical form %q of %q: %v", canonical, canonical, input, err)
+		}
+		if got := again.String(); got != canonical {
+			t.Fatalf("round trip of %q: got %q, want %q", input, got, canonical)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package taskquery
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func abs(s string) Date {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return Date{Abs: t}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Term
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"status:open", []Term{&StatusTerm{Node: Node{Pos: 1}, Name: "open"}}},
+		{"STATUS=Done", []Term{&StatusTerm{Node: Node{Pos: 1}, Name: "Done"}}},
+		{"status!=open", []Term{&StatusTerm{Node: Node{Pos: 1, Negated: true}, Name: "open"}}},
+		{"-status:open", []Term{&StatusTerm{Node: Node{Pos: 1, Negated: true}, Name: "open"}}},
+
+		// Two-character operators win over their one-character prefixes.
+		{"priority>=high", []Term{&PriorityTerm{Node: Node{Pos: 1}, Op: OpGe, Name: "high"}}},
+		{"priority<=low", []Term{&PriorityTerm{Node: Node{Pos: 1}, Op: OpLe, Name: "low"}}},
+		{"priority>low", []Term{&PriorityTerm{Node: Node{Pos: 1}, Op: OpGt, Name: "low"}}},
+		{"priority<high", []Term{&PriorityTerm{Node: Node{Pos: 1}, Op: OpLt, Name: "high"}}},
+		{"priority:=x", []Term{&PriorityTerm{Node: Node{Pos: 1}, Op: OpEq, Name: "=x"}}},
+		{"-priority>low", []Term{&PriorityTerm{Node: Node{Pos: 1, Negated: true}, Op: OpGt, Name: "low"}}},
+
+		{"due<2026-11-01", []Term{&DateTerm{Node: Node{Pos: 1}, Field: "due", Op: OpLt, Date: abs("2026-11-01")}}},
+		{"due:today", []Term{&DateTerm{Node: Node{Pos: 1}, Field: "due", Op: OpEq, Date: Date{Relative: true, Unit: 'd'}}}},
+		{"due<=today+7d", []Term{&DateTerm{Node: Node{Pos: 1}, Field: "due", Op: OpLe, Date: Date{Relative: true, Offset: 7, Unit: 'd'}}}},
+		{"created>Today-2W", []Term{&DateTerm{Node: Node{Pos: 1}, Field: "created", Op: OpGt, Date: Date{Relative: true, Offset: -2, Unit: 'w'}}}},
+		{"due:none", []Term{&DateTerm{Node: Node{Pos: 1}, Field: "due", Op: OpEq, None: true}}},
+		{"due!=NONE", []Term{&DateTerm{Node: Node{Pos: 1, Negated: true}, Field: "due", Op: OpEq, None: true}}},
+
+		{"cat:personal", []Term{&CategoryTerm{Node: Node{Pos: 1}, Name: "personal"}}},
+		{"-category:none", []Term{&CategoryTerm{Node: Node{Pos: 1, Negated: true}, None: true}}},
+		{"#work", []Term{&TagTerm{Node: Node{Pos: 1}, Name: "work"}}},
+		{`tag:"two words"`, []Term{&TagTerm{Node: Node{Pos: 1}, Name: "two words"}}},
+		{"-#later", []Term{&TagTerm{Node: Node{Pos: 1, Negated: true}, Name: "later"}}},
+		{"is:Overdue", []Term{&IsTerm{Node: Node{Pos: 1}, State: "overdue"}}},
+		{`title:"weekly \"sync\" \\ 1"`, []Term{&TitleTerm{Node: Node{Pos: 1}, Text: `weekly "sync" \ 1`}}},
+
+		{"report", []Term{&TextTerm{Node: Node{Pos: 1}, Text: "report"}}},
+		{`"release notes"`, []Term{&TextTerm{Node: Node{Pos: 1}, Text: "release notes", Phrase: true}}},
+		{`-"not this"`, []Term{&TextTerm{Node: Node{Pos: 1, Negated: true}, Text: "not this", Phrase: true}}},
+		// Only the first "-" negates; the rest is text.
+		{"--x", []Term{&TextTerm{Node: Node{Pos: 1, Negated: true}, Text: "-x"}}},
+		// Without an operator a field name is plain text.
+		{"status", []Term{&TextTerm{Node: Node{Pos: 1}, Text: "status"}}},
+		{"foo-bar", []Term{&TextTerm{Node: Node{Pos: 1}, Text: "foo-bar"}}},
+
+		// Positions count characters, not bytes, across several terms.
+		{`status:open  #work é "a b"`, []Term{
+			&StatusTerm{Node: Node{Pos: 1}, Name: "open"},
+			&TagTerm{Node: Node{Pos: 14}, Name: "work"},
+			&TextTerm{Node: Node{Pos: 20}, Text: "é"},
+			&TextTerm{Node: Node{Pos: 22}, Text: "a b", Phrase: true},
+		}},
+		// A quote ends a bare word.
+		{`ab"c d"`, []Term{
+			&TextTerm{Node: Node{Pos: 1}, Text: "ab"},
+			&TextTerm{Node: Node{Pos: 3}, Text: "c d", Phrase: true},
+		}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(q.Terms, tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, dump(q.Terms), dump(tt.want))
+		}
+	}
+}
+
+func dump(terms []Term) string {
+	s := ""
+	for _, t := range terms {
+		s += fmt.Sprintf("\n\t%T %s at %d", t, t, Base(t).Pos)
+	}
+	return s
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{"-", 1},
+		{"status:open - #x", 13},
+		{`"unterminated`, 1},
+		{`a "b`, 3},
+		{`title:"open`, 7},
+		{"#", 1},
+		{"a:b", 1},
+		{"x nope:1", 3},
+		{"status:", 8},
+		{`status:""`, 8},
+		{"-status!=open", 2},
+		{"status<open", 1},
+		{"tag>=x", 1},
+		{"is:sleeping", 4},
+		{"due<2026-13-40", 5},
+		{"due:tomorrow", 5},
+		{"due<none", 5},
+		{"created:none", 9},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q) error = %v, want *Error", tt.input, err)
+			continue
+		}
+		if perr.Pos != tt.pos {
+			t.Errorf("Parse(%q) error at %d (%v), want %d", tt.input, perr.Pos, perr, tt.pos)
+		}
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	long := make([]byte, MaxLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := Parse(string(long)); err == nil {
+		t.Error("expected an error for an over-long query")
+	}
+
+	many := ""
+	for i := 0; i <= MaxTerms; i++ {
+		many += "a "
+	}
+	var perr *Error
+	if _, err := Parse(many); !errors.As(err, &perr) || perr.Pos != 2*MaxTerms+1 {
+		t.Errorf("too many terms: got %v, want an error at %d", err, 2*MaxTerms+1)
+	}
+}
+
+func TestHas(t *testing.T) {
+	q, err := Parse("is:archived -is:done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Has("archived") || q.Has("done") || q.Has("open") {
+		t.Errorf("Has on %q gave the wrong answer", q)
+	}
+}
@@ -9,6 +9,8 @@ export default function CategoryDropdown({
   disabled = false,
 }) {
   const [open, setOpen] = useState(false);
+  // A category with tasks asks what to do with them before it is deleted.
+  const [pending, setPending] = useState(null);
   const rootRef = useRef(null);
 
   const items = useMemo(() => {
@@ -27,16 +29,29 @@ export default function CategoryDropdown({
       if (!rootRef.current) return;
       if (rootRef.current.contains(e.target)) return;
       setOpen(false);
+      setPending(null);
     }
     document.addEventListener("mousedown", onDocClick);
     return () => document.removeEventListener("mousedown", onDocClick);
   }, []);
 
-  async function handleDelete(e, id) {
+  async function handleDelete(e, c) {
     e.preventDefault();
     e.stopPropagation();
     if (!onDelete) return;
-    await onDelete(id);
+    if (c.task_count > 0) {
+      setPending({ id: c.id, tasks: "uncategorize" });
+      return;
+    }
+    await onDelete(c.id);
+  }
+
+  async function confirmDelete(e) {
+    e.preventDefault();
+    e.stopPropagation();
+    const { id, tasks } = pending;
+    setPending(null);
+    await onDelete(id, tasks);
   }
 
   return (
@@ -90,41 +105,87 @@ export default function CategoryDropdown({
               <div className="px-4 py-3 text-sm text-primary/60">No categories</div>
             ) : (
               items.map((c) => (
-                <div
-                  key={c.id}
-                  role="option"
-                  tabIndex={0}
-                  className={
-                    "group flex w-full cursor-pointer items-center justify-between px-4 py-2 text-left text-sm hover:bg-black/5 " +
-                    (String(value) === c.id ? "font-semibold" : "")
-                  }
-                  onClick={() => {
-                    onSelect?.(c.id);
-                    setOpen(false);
-                  }}
-                  onKeyDown={(e) => {
-                    if (e.key === "Enter" || e.key === " ") {
+                <div key={c.id}>
+                  <div
+                    role="option"
+                    tabIndex={0}
+                    className={
+                      "group flex w-full cursor-pointer items-center justify-between px-4 py-2 text-left text-sm hover:bg-black/5 " +
+                      (String(value) === c.id ? "font-semibold" : "")
+                    }
+                    onClick={() => {
                       onSelect?.(c.id);
                       setOpen(false);
-                    }
-                  }}
-                >
-                  <span className="truncate pr-3">{c.name}</span>
+                    }}
+                    onKeyDown={(e) => {
+                      if (e.key === "Enter" || e.key === " ") {
+                        onSelect?.(c.id);
+                        setOpen(false);
+                      }
+                    }}
+                  >
+                    <span className="truncate pr-3">{c.name}</span>
 
-                  <div className="flex items-center gap-2">
-                    <span className="text-xs text-primary/40">
-                      {String(value) === c.id ? "Selected" : ""}
-                    </span>
+                    <div className="flex items-center gap-2">
+                      <span className="text-xs text-primary/40">
+                        {String(value) === c.id ? "Selected" : ""}
+                      </span>
 
-                    <button
-                      type="button"
-                      className="rounded-lg p-1 text-primary/60 hover:bg-black/10 hover:text-primary"
-                      title="Delete category"
-                      onClick={(e) => handleDelete(e, c.id)}
-                    >
-                      <Trash2 className="h-4 w-4" />
-                    </button>
+                      <button
+                        type="button"
+                        className="rounded-lg p-1 text-primary/60 hover:bg-black/10 hover:text-primary"
+                        title="Delete category"
+                        onClick={(e) => handleDelete(e, c)}
+                      >
+                        <Trash2 className="h-4 w-4" />
+                      </button>
+                    </div>
                   </div>
+
+                  {pending?.id === c.id && (
+                    <div
+                      className="space-y-2 border-t border-black/5 bg-black/5 px-4 py-3 text-sm"
+                      onClick={(e) => e.stopPropagation()}
+                    >
+                      <p className="text-xs text-primary/70">
+                        {c.task_count} task{c.task_count === 1 ? "" : "s"} in this category:
+                      </p>
+                      <select
+                        value={pending.tasks}
+                        onChange={(e) => setPending({ id: c.id, tasks: e.target.value })}
+                        className="w-full rounded-lg border border-black/10 bg-white px-3 py-1.5 text-sm text-primary outline-none focus:ring-2 focus:ring-accent/60"
+                      >
+                        <option value="uncategorize">Move to Uncategorized</option>
+                        {items
+                          .filter((other) => other.id !== c.id)
+                          .map((other) => (
+                            <option key={other.id} value={`reassign:${other.id}`}>
+                              Move to {other.name}
+                            </option>
+                          ))}
+                        <option value="delete">Delete them too</option>
+                      </select>
+                      <div className="flex justify-end gap-2">
+                        <button
+                          type="button"
+                          className="rounded-lg px-3 py-1 text-xs text-primary/70 hover:bg-black/10"
+                          onClick={(e) => {
+                            e.stopPropagation();
+                            setPending(null);
+                          }}
+                        >
+                          Cancel
+                        </button>
+                        <button
+                          type="button"
+                          className="rounded-lg bg-primary px-3 py-1 text-xs font-semibold text-white hover:opacity-90"
+                          onClick={confirmDelete}
+                        >
+                          Delete category
+                        </button>
+                      </div>
+                    </div>
+                  )}
                 </div>
               ))
             )}
@@ -10,7 +10,12 @@ export async function createCategoryApi(name) {
   return res.data;
 }
 
-export async function deleteCategoryApi(id) {
-  const res = await api.delete(`/category/${id}`);
+// tasks says what happens to the tasks filed under the category:
+// "uncategorize", "reassign:{categoryID}" or "delete". The server refuses to
+// delete a category that still has tasks without it.
+export async function deleteCategoryApi(id, tasks) {
+  const res = await api.delete(`/category/${id}`, {
+    params: tasks ? { tasks } : undefined,
+  });
   return res.data;
 }
@@ -1,8 +1,11 @@
 import { api } from "./api.js";
 
-export async function getTasksApi() {
-  const res = await api.get("/task");
-  return res.data; 
+// getTasksApi fetches one page of tasks. params takes the filters and sort
+// of GET /task plus limit and cursor; pass the returned next_cursor back as
+// cursor for the page after.
+export async function getTasksApi(params) {
+  const res = await api.get("/task", { params });
+  return res.data;
 }
 
 export async function getTaskByIdApi(id) {
@@ -143,15 +143,13 @@ export default function AddTask() {
                   setCategoryId(nextId);
                   if (nextId) setNewCategory("");
                 }}
-                onDelete={async (deleteId) => {
+                onDelete={async (deleteId, tasks) => {
                   try {
-                    await deleteCategoryApi(deleteId);
+                    await deleteCategoryApi(deleteId, tasks);
 
-                    setCategories((curr) =>
-                      (Array.isArray(curr) ? curr : []).filter(
-                        (c) => String(c.id) !== String(deleteId)
-                      )
-                    );
+                    // Task counts and subcategories change with the delete.
+                    const data = await listCategoriesApi();
+                    setCategories(Array.isArray(data) ? data : []);
 
                     setCategoryId((curr) =>
                       String(curr) === String(deleteId) ? "" : curr
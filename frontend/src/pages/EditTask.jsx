@@ -34,6 +34,9 @@ export default function EditTask() {
     category_id: "",
     new_category: "",
   });
+  // The due date as stored, sent back unchanged unless the day is edited so
+  // a timed due date keeps its time.
+  const [storedDue, setStoredDue] = useState(null);
 
   useEffect(() => {
     let alive = true;
@@ -52,6 +55,11 @@ export default function EditTask() {
 
         setCategories(Array.isArray(cats) ? cats : []);
 
+        setStoredDue(
+          task?.due_date
+            ? { value: task.due_date, allDay: Boolean(task.due_all_day) }
+            : null
+        );
         setForm({
           title: task?.title || "",
           description: task?.description || "",
@@ -97,6 +105,11 @@ export default function EditTask() {
         status_id: Number(form.status_id),
       };
 
+      if (storedDue && form.due_date === storedDue.value.split("T")[0]) {
+        payload.due_date = storedDue.value;
+        payload.due_all_day = storedDue.allDay;
+      }
+
       const nc = form.new_category.trim();
       if (nc) {
         payload.category_name = nc;
@@ -185,15 +198,13 @@ export default function EditTask() {
                     new_category: nextId ? "" : s.new_category,
                   }));
                 }}
-                onDelete={async (deleteId) => {
+                onDelete={async (deleteId, tasks) => {
                   try {
-                    await deleteCategoryApi(deleteId);
+                    await deleteCategoryApi(deleteId, tasks);
 
-                    setCategories((curr) =>
-                      (Array.isArray(curr) ? curr : []).filter(
-                        (c) => String(c.id) !== String(deleteId)
-                      )
-                    );
+                    // Task counts and subcategories change with the delete.
+                    const data = await listCategoriesApi();
+                    setCategories(Array.isArray(data) ? data : []);
 
                     setForm((s) => ({
                       ...s,
@@ -2,21 +2,25 @@ import { useEffect, useMemo, useState } from "react";
 import { Link, useNavigate } from "react-router-dom";
 import { getSession } from "../lib/api.js";
 import { getTasksApi, deleteTaskApi, updateTaskApi } from "../lib/taskApi.js";
-
-function toYMD(dateString) {
-  if (!dateString) return null;
-  if (typeof dateString === "string" && dateString.includes("T")) return dateString.slice(0, 10);
-  return String(dateString).slice(0, 10);
-}
-
-function sortTasksByStatus(list) {
-  if (!Array.isArray(list)) return [];
-  const order = { 1: 1, 2: 2, 3: 3 };
-  return [...list].sort((a, b) => {
-    const sa = Number(a?.status_id) || 1;
-    const sb = Number(b?.status_id) || 1;
-    return (order[sa] || 1) - (order[sb] || 1);
-  });
+import { listCategoriesApi } from "../lib/categoryApi.js";
+
+const PAGE_SIZE = 50;
+
+const SORTS = [
+  { value: "created_at", label: "Newest" },
+  { value: "due_date", label: "Due date" },
+  { value: "priority", label: "Priority" },
+  { value: "updated_at", label: "Recently updated" },
+  { value: "title", label: "Title" },
+];
+
+// Filtering and sorting happen on the server; the list holds the pages
+// loaded so far.
+function taskQuery(filters) {
+  const params = { limit: PAGE_SIZE, sort: filters.sort };
+  if (filters.status_id) params.status_id = filters.status_id;
+  if (filters.search.trim()) params.search = filters.search.trim();
+  return params;
 }
 
 export default function HomePrivate() {
@@ -25,9 +29,15 @@ export default function HomePrivate() {
   const user = session?.user;
 
   const [tasks, setTasks] = useState([]);
+  const [nextCursor, setNextCursor] = useState(null);
   const [loading, setLoading] = useState(true);
+  const [loadingMore, setLoadingMore] = useState(false);
   const [errMsg, setErrMsg] = useState("");
 
+  const [filters, setFilters] = useState({ status_id: "", sort: "created_at", search: "" });
+  const [searchInput, setSearchInput] = useState("");
+  const [categoryCount, setCategoryCount] = useState(0);
+
   const [updatingIds, setUpdatingIds] = useState(() => new Set());
 
   function markUpdating(id, on) {
@@ -50,47 +60,67 @@ export default function HomePrivate() {
     setLoading(true);
     setErrMsg("");
     try {
-      const data = await getTasksApi();
+      const data = await getTasksApi(taskQuery(filters));
       setTasks(normalizeTasks(data));
+      setNextCursor(data?.next_cursor || null);
     } catch (err) {
       setTasks([]);
+      setNextCursor(null);
       setErrMsg(err?.normalizedMessage || "Gagal memuat tasks.");
     } finally {
       setLoading(false);
     }
   }
 
+  async function loadMore() {
+    if (!nextCursor || loadingMore) return;
+    setLoadingMore(true);
+    setErrMsg("");
+    try {
+      const data = await getTasksApi({ ...taskQuery(filters), cursor: nextCursor });
+      setTasks((curr) => [...curr, ...normalizeTasks(data)]);
+      setNextCursor(data?.next_cursor || null);
+    } catch (err) {
+      setErrMsg(err?.normalizedMessage || "Gagal memuat tasks.");
+    } finally {
+      setLoadingMore(false);
+    }
+  }
+
   useEffect(() => {
     if (!user) {
       navigate("/login", { replace: true });
       return;
     }
     load();
+  }, [filters]);
+
+  useEffect(() => {
+    if (!user) return;
+    listCategoriesApi()
+      .then((data) => setCategoryCount(Array.isArray(data) ? data.length : 0))
+      .catch(() => {});
   }, []);
 
+  // Counts cover the pages loaded so far; "+" marks that there are more.
   const stats = useMemo(() => {
-    const total = Array.isArray(tasks) ? tasks.length : 0;
+    const more = nextCursor ? "+" : "";
+    const total = (Array.isArray(tasks) ? tasks.length : 0) + more;
     const done = (Array.isArray(tasks) ? tasks : []).filter(
       (t) =>
         String(t?.status_name || "").toLowerCase() === "done" ||
         String(t?.status || "").toLowerCase() === "done" ||
         Number(t?.status_id) === 3
-    ).length;
+    ).length + more;
 
-    const categorySet = new Set(
-      (Array.isArray(tasks) ? tasks : [])
-        .map((t) => t?.category_name || t?.category || "")
-        .filter(Boolean)
-    );
-
-    return { total, done, categories: categorySet.size };
-  }, [tasks]);
+    return { total, done, categories: categoryCount };
+  }, [tasks, nextCursor, categoryCount]);
 
   async function onDelete(id) {
     if (!id) return;
     try {
       await deleteTaskApi(id);
-      await load();
+      setTasks((curr) => (Array.isArray(curr) ? curr : []).filter((t) => t?.id !== id));
     } catch (err) {
       setErrMsg(err?.normalizedMessage || "Gagal menghapus task.");
     }
@@ -118,7 +148,10 @@ export default function HomePrivate() {
       await updateTaskApi(id, {
         title: task?.title || "(untitled)",
         description: task?.description ?? null,
-        due_date: toYMD(task?.due_date),
+        // Send the stored due date back as is; due_all_day keeps a timed
+        // due date from being read as a date-only one.
+        due_date: task?.due_date ?? null,
+        due_all_day: Boolean(task?.due_all_day),
         priority_id: Number(task?.priority_id) || 1,
         status_id: next,
         category_id: task?.category_id ?? null,
@@ -133,8 +166,6 @@ export default function HomePrivate() {
 
   if (!user) return null;
 
-  const sortedTasks = useMemo(() => sortTasksByStatus(tasks), [tasks]);
-
   return (
     <main className="mx-auto w-full max-w-6xl px-6 py-10">
       <section className="flex flex-col gap-4 md:flex-row md:items-end md:justify-between">
@@ -167,8 +198,49 @@ export default function HomePrivate() {
       ) : null}
 
       <section className="mt-8">
-        <div className="flex items-center justify-between gap-3">
+        <div className="flex flex-col gap-3 md:flex-row md:items-center md:justify-between">
           <h2 className="text-xl font-extrabold text-primary">Your Tasks</h2>
+
+          <div className="flex flex-col gap-2 sm:flex-row">
+            <form
+              onSubmit={(e) => {
+                e.preventDefault();
+                setFilters((f) => ({ ...f, search: searchInput }));
+              }}
+            >
+              <input
+                value={searchInput}
+                onChange={(e) => setSearchInput(e.target.value)}
+                placeholder="Cari task..."
+                className="w-full rounded-xl border border-black/10 bg-white px-4 py-2 text-sm text-primary outline-none focus:ring-2 focus:ring-accent/60"
+              />
+            </form>
+
+            <select
+              value={filters.status_id}
+              onChange={(e) => setFilters((f) => ({ ...f, status_id: e.target.value }))}
+              className="rounded-xl border border-black/10 bg-white px-4 py-2 text-sm text-primary outline-none focus:ring-2 focus:ring-accent/60"
+              aria-label="Filter status"
+            >
+              <option value="">All statuses</option>
+              <option value="1">Todo</option>
+              <option value="2">Doing</option>
+              <option value="3">Done</option>
+            </select>
+
+            <select
+              value={filters.sort}
+              onChange={(e) => setFilters((f) => ({ ...f, sort: e.target.value }))}
+              className="rounded-xl border border-black/10 bg-white px-4 py-2 text-sm text-primary outline-none focus:ring-2 focus:ring-accent/60"
+              aria-label="Sort tasks"
+            >
+              {SORTS.map((o) => (
+                <option key={o.value} value={o.value}>
+                  {o.label}
+                </option>
+              ))}
+            </select>
+          </div>
         </div>
 
         {loading ? (
@@ -177,7 +249,7 @@ export default function HomePrivate() {
           </div>
         ) : null}
 
-        {!loading && (!sortedTasks || sortedTasks.length === 0) ? (
+        {!loading && (!tasks || tasks.length === 0) ? (
           <div className="mt-4 rounded-2xl border border-primary/15 bg-white/60 p-6">
             <div className="text-lg font-bold text-primary">Belum ada task</div>
             <Link
@@ -189,9 +261,9 @@ export default function HomePrivate() {
           </div>
         ) : null}
 
-        {!loading && Array.isArray(sortedTasks) && sortedTasks.length > 0 ? (
+        {!loading && Array.isArray(tasks) && tasks.length > 0 ? (
           <div className="mt-4 grid gap-4">
-            {sortedTasks.map((t) => (
+            {tasks.map((t) => (
               <TaskCard
                 key={t.id || `${t.title}-${t.created_at}`}
                 task={t}
@@ -202,6 +274,19 @@ export default function HomePrivate() {
             ))}
           </div>
         ) : null}
+
+        {!loading && nextCursor ? (
+          <div className="mt-6 flex justify-center">
+            <button
+              type="button"
+              onClick={loadMore}
+              disabled={loadingMore}
+              className="rounded-xl border border-primary/15 bg-white/70 px-5 py-2 text-sm font-bold text-primary hover:bg-white transition disabled:cursor-not-allowed disabled:opacity-60"
+            >
+              {loadingMore ? "Loading..." : "Load more"}
+            </button>
+          </div>
+        ) : null}
       </section>
     </main>
   );